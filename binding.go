@@ -1,12 +1,22 @@
 package inject
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 )
 
+// describedBinding is implemented by both bindings and resolved bindings
+type describedBinding interface {
+	kind() Kind
+	// the dependencies this binding needs to be resolved, including optional and lazy ones
+	dependencies() []dependency
+}
+
 type binding interface {
 	fmt.Stringer
+	describedBinding
 	// has to be a copy constructor
 	// https://github.com/peter-edge/inject-go/commit/e525825afc80f0de819f35a6afc26a4bf3d3a192
 	// this could be designed better
@@ -15,8 +25,11 @@ type binding interface {
 
 type resolvedBinding interface {
 	fmt.Stringer
+	describedBinding
 	validate() error
-	get() (interface{}, error)
+	get(ctx context.Context, r *resolution) (interface{}, error)
+	// whether the value was already constructed
+	constructed() bool
 }
 
 type intermediateBinding struct {
@@ -27,16 +40,65 @@ func newIntermediateBinding(to interface{}) binding {
 	return &intermediateBinding{newBindingKey(reflect.TypeOf(to))}
 }
 
+func newTaggedIntermediateBinding(tag string) func(interface{}) binding {
+	return func(to interface{}) binding {
+		return &intermediateBinding{newTaggedBindingKey(reflect.TypeOf(to), tag)}
+	}
+}
+
 func (i *intermediateBinding) String() string {
 	return i.bindingKey.String()
 }
 
+func (i *intermediateBinding) kind() Kind {
+	return IntermediateKind
+}
+
+func (i *intermediateBinding) dependencies() []dependency {
+	return []dependency{{bindingKey: i.bindingKey, reflectType: i.bindingKey.reflectType()}}
+}
+
 func (i *intermediateBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
-	binding, ok := module.binding(i.bindingKey)
-	if !ok {
-		return nil, errNoFinalBinding.withTag("bindingKey", i.bindingKey)
+	return &resolvedIntermediateBinding{i, injector}, nil
+}
+
+// resolvedIntermediateBinding gets its values from the binding of the injector, or
+// of a parent injector, for the binding key it links to, which can be another
+// intermediate binding
+type resolvedIntermediateBinding struct {
+	intermediateBinding *intermediateBinding
+	injector            *injector
+}
+
+func (r *resolvedIntermediateBinding) String() string {
+	return r.intermediateBinding.String()
+}
+
+func (r *resolvedIntermediateBinding) validate() error {
+	if _, err := r.injector.getBinding(r.intermediateBinding.bindingKey); err != nil {
+		if errors.Is(err, ErrNoBinding) {
+			return ErrNoFinalBinding.withBindingKey(r.intermediateBinding.bindingKey)
+		}
+		return err
 	}
-	return binding.resolvedBinding(module, injector)
+	return nil
+}
+
+func (r *resolvedIntermediateBinding) get(ctx context.Context, res *resolution) (interface{}, error) {
+	return r.injector.get(ctx, res, r.intermediateBinding.bindingKey)
+}
+
+func (r *resolvedIntermediateBinding) constructed() bool {
+	binding, err := r.injector.getBinding(r.intermediateBinding.bindingKey)
+	return err == nil && binding.constructed()
+}
+
+func (r *resolvedIntermediateBinding) kind() Kind {
+	return IntermediateKind
+}
+
+func (r *resolvedIntermediateBinding) dependencies() []dependency {
+	return r.intermediateBinding.dependencies()
 }
 
 type singletonBinding struct {
@@ -56,10 +118,22 @@ func (s *singletonBinding) validate() error {
 	return nil
 }
 
-func (s *singletonBinding) get() (interface{}, error) {
+func (s *singletonBinding) get(ctx context.Context, r *resolution) (interface{}, error) {
 	return s.singleton, nil
 }
 
+func (s *singletonBinding) constructed() bool {
+	return true
+}
+
+func (s *singletonBinding) kind() Kind {
+	return SingletonKind
+}
+
+func (s *singletonBinding) dependencies() []dependency {
+	return nil
+}
+
 func (s *singletonBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
 	return &singletonBinding{s.singleton, injector}, nil
 }
@@ -68,20 +142,24 @@ type constructorBinding struct {
 	constructor interface{}
 	cache       *constructorBindingCache
 	injector    *injector
+	onConstruct []onConstructHook
 }
 
 type constructorBindingCache struct {
-	numIn       int
-	bindingKeys []bindingKey
+	numIn        int
+	dependencies []dependency
+	// whether the first parameter is a context.Context
+	context bool
 }
 
 func newConstructorBinding(constructor interface{}) binding {
-	return &constructorBinding{constructor, newConstructorBindingCache(constructor), nil}
+	return &constructorBinding{constructor, newConstructorBindingCache(constructor), nil, nil}
 }
 
 func newConstructorBindingCache(constructor interface{}) *constructorBindingCache {
-	bindingKeys := getParameterBindingKeysForFunc(reflect.TypeOf(constructor))
-	return &constructorBindingCache{len(bindingKeys), bindingKeys}
+	constructorReflectType := reflect.TypeOf(constructor)
+	dependencies := getParameterDependenciesForFunc(constructorReflectType)
+	return &constructorBindingCache{len(dependencies), dependencies, isContextFunc(constructorReflectType)}
 }
 
 func (c *constructorBinding) String() string {
@@ -89,19 +167,34 @@ func (c *constructorBinding) String() string {
 }
 
 func (c *constructorBinding) validate() error {
-	return c.injector.validateBindingKeys(c.cache.bindingKeys)
+	return c.injector.validateDependencies(c.cache.dependencies)
 }
 
-func (c *constructorBinding) get() (interface{}, error) {
-	reflectValues, err := c.injector.getReflectValues(c.cache.bindingKeys)
+func (c *constructorBinding) get(ctx context.Context, r *resolution) (interface{}, error) {
+	reflectValues, err := c.injector.getReflectValues(ctx, r, c.cache.dependencies)
 	if err != nil {
 		return nil, err
 	}
-	return callConstructor(c.constructor, reflectValues)
+	if c.cache.context {
+		reflectValues = prependContextReflectValue(ctx, reflectValues)
+	}
+	return constructAndInitialize(ctx, r, c.constructor, reflectValues, c.onConstruct)
+}
+
+func (c *constructorBinding) constructed() bool {
+	return false
+}
+
+func (c *constructorBinding) kind() Kind {
+	return ConstructorKind
+}
+
+func (c *constructorBinding) dependencies() []dependency {
+	return c.cache.dependencies
 }
 
 func (c *constructorBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
-	return &constructorBinding{c.constructor, c.cache, injector}, nil
+	return &constructorBinding{c.constructor, c.cache, injector, c.onConstruct}, nil
 }
 
 type singletonConstructorBinding struct {
@@ -110,41 +203,81 @@ type singletonConstructorBinding struct {
 }
 
 func newSingletonConstructorBinding(constructor interface{}) binding {
-	return &singletonConstructorBinding{constructorBinding{constructor, newConstructorBindingCache(constructor), nil}, nil}
+	return &singletonConstructorBinding{constructorBinding{constructor, newConstructorBindingCache(constructor), nil, nil}, nil}
 }
 
 func (s *singletonConstructorBinding) String() string {
 	return fmt.Sprintf("%v", s.constructor)
 }
 
-func (s *singletonConstructorBinding) get() (interface{}, error) {
-	return s.loader.load(s.constructorBinding.get)
+func (s *singletonConstructorBinding) constructed() bool {
+	return s.loader.loaded()
+}
+
+func (s *singletonConstructorBinding) kind() Kind {
+	return SingletonConstructorKind
+}
+
+func (s *singletonConstructorBinding) get(ctx context.Context, r *resolution) (interface{}, error) {
+	return s.loader.load(func() (interface{}, error) { return s.constructorBinding.get(detachContext(ctx), r) })
 }
 
 func (s *singletonConstructorBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
-	return &singletonConstructorBinding{constructorBinding{s.constructorBinding.constructor, s.constructorBinding.cache, injector}, newLoader()}, nil
+	return &singletonConstructorBinding{constructorBinding{s.constructorBinding.constructor, s.constructorBinding.cache, injector, s.constructorBinding.onConstruct}, newLoader(injector.constructed.add)}, nil
+}
+
+type scopedBinding struct {
+	binding
+	scope Scope
+}
+
+func newScopedBinding(binding binding, scope Scope) binding {
+	return &scopedBinding{binding, scope}
+}
+
+func (s *scopedBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
+	resolvedBinding, err := s.binding.resolvedBinding(module, injector)
+	if err != nil {
+		return nil, err
+	}
+	unscoped := func(ctx context.Context) (interface{}, error) {
+		ctx, r := splitResolution(ctx)
+		return resolvedBinding.get(ctx, r)
+	}
+	return &resolvedScopedBinding{resolvedBinding, s.scope, s.scope.Scope(unscoped)}, nil
+}
+
+type resolvedScopedBinding struct {
+	resolvedBinding
+	scope  Scope
+	scoped func(context.Context) (interface{}, error)
+}
+
+func (r *resolvedScopedBinding) get(ctx context.Context, res *resolution) (interface{}, error) {
+	return r.scoped(withResolution(ctx, res))
 }
 
 type taggedConstructorBinding struct {
 	constructor interface{}
 	cache       *taggedConstructorBindingCache
 	injector    *injector
+	onConstruct []onConstructHook
 }
 
 type taggedConstructorBindingCache struct {
 	inReflectType reflect.Type
 	numFields     int
-	bindingKeys   []bindingKey
+	dependencies  []dependency
 }
 
 func newTaggedConstructorBinding(constructor interface{}) binding {
-	return &taggedConstructorBinding{constructor, newTaggedConstructorBindingCache(constructor), nil}
+	return &taggedConstructorBinding{constructor, newTaggedConstructorBindingCache(constructor, taggedFuncStructFieldTag), nil, nil}
 }
 
-func newTaggedConstructorBindingCache(constructor interface{}) *taggedConstructorBindingCache {
+func newTaggedConstructorBindingCache(constructor interface{}, tagKey string) *taggedConstructorBindingCache {
 	constructorReflectType := reflect.TypeOf(constructor)
-	bindingKeys := getParameterBindingKeysForTaggedFunc(constructorReflectType)
-	return &taggedConstructorBindingCache{constructorReflectType.In(0), len(bindingKeys), bindingKeys}
+	dependencies := getParameterDependenciesForTaggedFunc(constructorReflectType, tagKey)
+	return &taggedConstructorBindingCache{constructorReflectType.In(0), len(dependencies), dependencies}
 }
 
 func (t *taggedConstructorBinding) String() string {
@@ -152,21 +285,50 @@ func (t *taggedConstructorBinding) String() string {
 }
 
 func (t *taggedConstructorBinding) validate() error {
-	return t.injector.validateBindingKeys(t.cache.bindingKeys)
+	return t.injector.validateDependencies(t.cache.dependencies)
 }
 
-func (t *taggedConstructorBinding) get() (interface{}, error) {
-	reflectValues, err := t.injector.getReflectValues(t.cache.bindingKeys)
+func (t *taggedConstructorBinding) get(ctx context.Context, r *resolution) (interface{}, error) {
+	reflectValues, err := t.injector.getReflectValues(ctx, r, t.cache.dependencies)
 	if err != nil {
 		return nil, err
 	}
 	structReflectValue := newStructReflectValue(t.cache.inReflectType)
 	populateStructReflectValue(&structReflectValue, reflectValues)
-	return callConstructor(t.constructor, []reflect.Value{structReflectValue})
+	return constructAndInitialize(ctx, r, t.constructor, []reflect.Value{structReflectValue}, t.onConstruct)
+}
+
+func (t *taggedConstructorBinding) constructed() bool {
+	return false
+}
+
+func (t *taggedConstructorBinding) kind() Kind {
+	return TaggedConstructorKind
+}
+
+func (t *taggedConstructorBinding) dependencies() []dependency {
+	return t.cache.dependencies
 }
 
 func (t *taggedConstructorBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
-	return &taggedConstructorBinding{t.constructor, t.cache, injector}, nil
+	cache, err := t.resolvedCache(injector)
+	if err != nil {
+		return nil, err
+	}
+	return &taggedConstructorBinding{t.constructor, cache, injector, t.onConstruct}, nil
+}
+
+// resolvedCache verifies the fields of the struct parameter with the struct tag
+// key of the injector, and gets the dependencies again for other tag keys
+func (t *taggedConstructorBinding) resolvedCache(injector *injector) (*taggedConstructorBindingCache, error) {
+	tagKey := injector.options.tagKey()
+	if err := verifyStructCanBePopulated(t.cache.inReflectType, tagKey); err != nil {
+		return nil, err
+	}
+	if tagKey == taggedFuncStructFieldTag {
+		return t.cache, nil
+	}
+	return newTaggedConstructorBindingCache(t.constructor, tagKey), nil
 }
 
 type taggedSingletonConstructorBinding struct {
@@ -175,28 +337,70 @@ type taggedSingletonConstructorBinding struct {
 }
 
 func newTaggedSingletonConstructorBinding(constructor interface{}) binding {
-	return &taggedSingletonConstructorBinding{taggedConstructorBinding{constructor, newTaggedConstructorBindingCache(constructor), nil}, nil}
+	return &taggedSingletonConstructorBinding{taggedConstructorBinding{constructor, newTaggedConstructorBindingCache(constructor, taggedFuncStructFieldTag), nil, nil}, nil}
 }
 
 func (t *taggedSingletonConstructorBinding) String() string {
 	return fmt.Sprintf("%v", t.constructor)
 }
 
-func (t *taggedSingletonConstructorBinding) get() (interface{}, error) {
-	return t.loader.load(t.taggedConstructorBinding.get)
+func (t *taggedSingletonConstructorBinding) constructed() bool {
+	return t.loader.loaded()
+}
+
+func (t *taggedSingletonConstructorBinding) kind() Kind {
+	return TaggedSingletonConstructorKind
+}
+
+func (t *taggedSingletonConstructorBinding) get(ctx context.Context, r *resolution) (interface{}, error) {
+	return t.loader.load(func() (interface{}, error) { return t.taggedConstructorBinding.get(detachContext(ctx), r) })
 }
 
 func (t *taggedSingletonConstructorBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
-	return &taggedSingletonConstructorBinding{taggedConstructorBinding{t.taggedConstructorBinding.constructor, t.taggedConstructorBinding.cache, injector}, newLoader()}, nil
+	cache, err := t.taggedConstructorBinding.resolvedCache(injector)
+	if err != nil {
+		return nil, err
+	}
+	return &taggedSingletonConstructorBinding{taggedConstructorBinding{t.taggedConstructorBinding.constructor, cache, injector, t.taggedConstructorBinding.onConstruct}, newLoader(injector.constructed.add)}, nil
 }
 
-func callConstructor(constructor interface{}, reflectValues []reflect.Value) (interface{}, error) {
+// callConstructor wraps the error returned by the constructor, if any, in an
+// ErrConstructorFailed with the error as Cause, and returns an
+// ErrConstructorPanicked if the constructor panics
+func callConstructor(constructor interface{}, reflectValues []reflect.Value) (_ interface{}, retErr error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			retErr = withPanic(ErrConstructorPanicked.withTag("constructor", reflect.TypeOf(constructor)), recovered)
+		}
+	}()
 	returnValues := reflect.ValueOf(constructor).Call(reflectValues)
 	if len(returnValues) == 2 {
 		ret := returnValues[1].Interface()
 		if ret != nil {
-			return nil, ret.(error)
+			return nil, ErrConstructorFailed.withTag("constructor", reflect.TypeOf(constructor)).withCause(ret.(error))
 		}
 	}
 	return returnValues[0].Interface(), nil
 }
+
+// withPanic adds the recovered value of a panic to err, as Cause if it is an error
+func withPanic(err *Error, recovered interface{}) *Error {
+	err = err.withTag("panic", recovered)
+	if cause, ok := recovered.(error); ok {
+		err = err.withCause(cause)
+	}
+	return err
+}
+
+// constructAndInitialize calls the constructor, then initializes the constructed
+// value with its Init method and the OnConstruct hooks of the binding
+func constructAndInitialize(ctx context.Context, r *resolution, constructor interface{}, reflectValues []reflect.Value, onConstruct []onConstructHook) (interface{}, error) {
+	value, err := callConstructor(constructor, reflectValues)
+	if err != nil {
+		return nil, err
+	}
+	if err := initialize(ctx, r, value, onConstruct); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,130 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Kind describes how a binding provides its values.
+type Kind string
+
+const (
+	// IntermediateKind is a binding of an interface to another binding, see InterfaceBuilder.To.
+	IntermediateKind Kind = "intermediate"
+	// SingletonKind is a binding to a value, see Builder.ToSingleton.
+	SingletonKind Kind = "singleton"
+	// ConstructorKind is a binding to a constructor called for every value, see Builder.ToConstructor.
+	ConstructorKind Kind = "constructor"
+	// SingletonConstructorKind is a binding to a constructor called once, see Builder.ToSingletonConstructor.
+	SingletonConstructorKind Kind = "singleton constructor"
+	// TaggedConstructorKind is like ConstructorKind, see Builder.ToTaggedConstructor.
+	TaggedConstructorKind Kind = "tagged constructor"
+	// TaggedSingletonConstructorKind is like SingletonConstructorKind, see Builder.ToTaggedSingletonConstructor.
+	TaggedSingletonConstructorKind Kind = "tagged singleton constructor"
+	// FactoryKind is a binding of a function type to a constructor, see Builder.ToFactory.
+	FactoryKind Kind = "factory"
+	// SetKind is a multibinding of a slice, see Module.BindSet.
+	SetKind Kind = "set"
+	// MapKind is a multibinding of a map, see Module.BindMap.
+	MapKind Kind = "map"
+	// ExposedKind is a binding of a private module, see PrivateModule.Expose.
+	ExposedKind Kind = "exposed"
+)
+
+// Key identifies a binding.
+type Key struct {
+	// The bound type, which is the interface itself for interfaces.
+	Type reflect.Type
+	// Empty if the binding is not tagged.
+	Tag string
+}
+
+func newKey(bindingKey bindingKey) Key {
+	return Key{elementReflectType(bindingKey.reflectType()), bindingKeyTag(bindingKey)}
+}
+
+func (k Key) String() string {
+	if k.Tag == "" {
+		return fmt.Sprintf("{type:%s}", k.Type)
+	}
+	return fmt.Sprintf("{type:%s tag:%s}", k.Type, k.Tag)
+}
+
+// BindingInfo describes a binding of a Module or an Injector.
+type BindingInfo struct {
+	// The key of the binding.
+	Key  Key
+	Kind Kind
+	// The keys of all parameters and fields that are injected to construct
+	// values, including optional ones and providers.
+	Dependencies []Key
+	// Nil if the binding is not scoped.
+	Scope Scope
+	// The module the binding was declared in. For multibindings, the
+	// module that declared the first element.
+	Module Module
+	// Whether the value was already constructed, which is true for bindings
+	// to singletons, and for singleton constructors once they were called
+	// by the Injector.
+	Constructed bool
+}
+
+func (m *module) Bindings() []BindingInfo {
+	bindingInfos := make([]BindingInfo, 0, len(m.bindings))
+	for _, bindingKey := range sortedBindingKeys(m.bindings) {
+		binding := m.bindings[bindingKey]
+		bindingInfos = append(bindingInfos, newBindingInfo(bindingKey, binding, m.declaringModules[bindingKey], binding.kind() == SingletonKind))
+	}
+	return bindingInfos
+}
+
+func (i *injector) Bindings() []BindingInfo {
+	var bindingInfos []BindingInfo
+	seen := make(map[bindingKey]bool)
+	for injector := i; injector != nil; injector = injector.parent {
+		for bindingKey, resolvedBinding := range injector.bindings {
+			if seen[bindingKey] {
+				continue
+			}
+			seen[bindingKey] = true
+			bindingInfos = append(bindingInfos, newBindingInfo(bindingKey, resolvedBinding, injector.declaringModules[bindingKey], resolvedBinding.constructed()))
+		}
+	}
+	sort.Slice(bindingInfos, func(i int, j int) bool {
+		return bindingInfos[i].Key.String() < bindingInfos[j].Key.String()
+	})
+	return bindingInfos
+}
+
+func newBindingInfo(bindingKey bindingKey, binding describedBinding, declaringModule *module, constructed bool) BindingInfo {
+	dependencies := binding.dependencies()
+	dependencyKeys := make([]Key, len(dependencies))
+	for i, dependency := range dependencies {
+		dependencyKeys[i] = newKey(dependency.bindingKey)
+	}
+	bindingInfo := BindingInfo{
+		Key:          newKey(bindingKey),
+		Kind:         binding.kind(),
+		Dependencies: dependencyKeys,
+		Scope:        bindingScope(binding),
+		Constructed:  constructed,
+	}
+	// not a nil *module in a non-nil Module
+	if declaringModule != nil {
+		bindingInfo.Module = declaringModule
+	}
+	return bindingInfo
+}
+
+// bindingScope returns the scope of a scoped binding, or nil otherwise
+func bindingScope(binding describedBinding) Scope {
+	switch binding := binding.(type) {
+	case *scopedBinding:
+		return binding.scope
+	case *resolvedScopedBinding:
+		return binding.scope
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	simpleInterfaceReflectType = reflect.TypeOf((*SimpleInterface)(nil)).Elem()
+	barInterfaceReflectType    = reflect.TypeOf((*BarInterface)(nil)).Elem()
+	secondInterfaceReflectType = reflect.TypeOf((*SecondInterface)(nil)).Elem()
+)
+
+func TestModuleBindings(t *testing.T) {
+	installed := NewModule()
+	installed.BindTagged("tagOne", (*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).In(RequestScope).ToConstructor(createSecondInterface)
+	module.Install(installed)
+
+	bindings := module.Bindings()
+	require.Equal(t, []BindingInfo{
+		{
+			Key:          Key{secondInterfaceReflectType, ""},
+			Kind:         ConstructorKind,
+			Dependencies: []Key{{simpleInterfaceReflectType, ""}, {barInterfaceReflectType, ""}},
+			Scope:        RequestScope,
+			Module:       module,
+		},
+		{
+			Key:          Key{simpleInterfaceReflectType, "tagOne"},
+			Kind:         SingletonKind,
+			Dependencies: []Key{},
+			Module:       installed,
+			Constructed:  true,
+		},
+	}, bindings)
+}
+
+func TestInjectorBindings(t *testing.T) {
+	parentModule := NewModule()
+	parentModule.Bind((*SimpleInterface)(nil)).ToSingletonConstructor(createSimplePtrInterface)
+	parentInjector, err := NewInjector(parentModule)
+	require.NoError(t, err)
+	childModule := NewModule()
+	childModule.BindInterface((*BarInterface)(nil)).To(&BarPtrStruct{})
+	childModule.Bind(&BarPtrStruct{}).ToSingleton(&BarPtrStruct{1})
+	childInjector, err := parentInjector.NewChildInjector(childModule)
+	require.NoError(t, err)
+
+	bindings := childInjector.Bindings()
+	require.Len(t, bindings, 4)
+	require.Equal(t, Key{reflect.TypeOf(&BarPtrStruct{}), ""}, bindings[0].Key)
+	require.Equal(t, Key{barInterfaceReflectType, ""}, bindings[1].Key)
+	require.Equal(t, IntermediateKind, bindings[1].Kind)
+	require.Equal(t, []Key{{reflect.TypeOf(&BarPtrStruct{}), ""}}, bindings[1].Dependencies)
+	require.Equal(t, childModule, bindings[1].Module)
+	// the key is not embedded, so printing a BindingInfo prints all of it
+	require.Contains(t, fmt.Sprint(bindings[1]), "intermediate")
+	require.Equal(t, Key{reflect.TypeOf((*Injector)(nil)).Elem(), ""}, bindings[2].Key)
+	require.True(t, bindings[2].Constructed)
+	require.Equal(t, Key{simpleInterfaceReflectType, ""}, bindings[3].Key)
+	require.Equal(t, SingletonConstructorKind, bindings[3].Kind)
+	require.Equal(t, parentModule, bindings[3].Module)
+	require.Nil(t, bindings[3].Scope)
+	require.False(t, bindings[3].Constructed)
+
+	_, err = childInjector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.True(t, childInjector.Bindings()[3].Constructed)
+	require.True(t, parentInjector.Bindings()[1].Constructed)
+}
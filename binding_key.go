@@ -3,6 +3,8 @@ package inject
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 type bindingKey interface {
@@ -38,3 +40,31 @@ func newTaggedBindingKey(reflectType reflect.Type, tag string) bindingKey {
 func (t taggedBindingKey) String() string {
 	return fmt.Sprintf("{type:%s tag:%s}", t.reflectType().String(), t.tag)
 }
+
+// bindingKeyTag returns the tag of the binding key, or "" if it is not tagged
+func bindingKeyTag(bindingKey bindingKey) string {
+	if taggedBindingKey, ok := bindingKey.(taggedBindingKey); ok {
+		return taggedBindingKey.tag
+	}
+	return ""
+}
+
+// sortedBindingKeys returns the keys of the given bindings sorted by their string representation
+func sortedBindingKeys[B any](bindings map[bindingKey]B) []bindingKey {
+	bindingKeys := make([]bindingKey, 0, len(bindings))
+	for bindingKey := range bindings {
+		bindingKeys = append(bindingKeys, bindingKey)
+	}
+	sort.Slice(bindingKeys, func(i int, j int) bool {
+		return bindingKeys[i].String() < bindingKeys[j].String()
+	})
+	return bindingKeys
+}
+
+func bindingKeyPathString(bindingKeys []bindingKey) string {
+	s := make([]string, len(bindingKeys))
+	for i, bindingKey := range bindingKeys {
+		s[i] = bindingKey.String()
+	}
+	return strings.Join(s, " -> ")
+}
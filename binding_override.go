@@ -19,20 +19,38 @@ func (o *override) With(overrides ...Module) Module {
 	m := newModule()
 	addBindings(m, o.source)
 	for _, om := range overrides {
-		addBindings(m, om.(*module))
+		castModule, ok := castModule(om)
+		if !ok {
+			m.addBindingError(ErrCannotCastModule)
+			continue
+		}
+		addBindings(m, castModule)
 	}
 	return m
 }
 
 func addBindings(target *module, source *module) {
 	for k, v := range source.bindings {
+		// map bindings are combined instead of replaced
+		if found, ok := target.bindings[k]; ok {
+			if merged, ok := overrideBindings(found, v); ok {
+				v = merged
+			} else {
+				target.declaringModules[k] = source.declaringModules[k]
+			}
+		} else {
+			target.declaringModules[k] = source.declaringModules[k]
+		}
 		target.bindings[k] = v
 	}
 	// also add any binding errors from the source modules, because
 	// error checking is only done at creation of the injector
 	target.bindingErrors = append(target.bindingErrors, source.bindingErrors...)
+	target.duplicateBindings = append(target.duplicateBindings, source.duplicateBindings...)
 	// plus the eager singletons
 	target.eager = append(target.eager, source.eager...)
+	// private modules are not overridden
+	target.privateModules = append(target.privateModules, source.privateModules...)
 }
 
 // Override returns a builder that allows replacing bindings of the given
@@ -41,5 +59,10 @@ func addBindings(target *module, source *module) {
 // with test bindings:
 //   module := Override(productionModule).With(testModule)
 func Override(source Module) OverrideBuilder {
-	return &override{source: source.(*module)}
+	castModule, ok := castModule(source)
+	if !ok {
+		castModule = newModule()
+		castModule.addBindingError(ErrCannotCastModule)
+	}
+	return &override{source: castModule}
 }
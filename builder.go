@@ -14,8 +14,18 @@ func newNoOpBuilder() InterfaceBuilder {
 	return &noOpBuilder{}
 }
 
+func (n *noOpBuilder) In(scope Scope) Builder {
+	return n
+}
+
+func (n *noOpBuilder) OnConstruct(fn interface{}) Builder {
+	return n
+}
+
 func (n *noOpBuilder) To(to interface{}) {}
 
+func (n *noOpBuilder) ToTagged(tag string, to interface{}) {}
+
 func (n *noOpBuilder) ToSingleton(singleton interface{}) {}
 
 func (n *noOpBuilder) ToConstructor(constructor interface{}) {}
@@ -30,48 +40,139 @@ func (n *noOpBuilder) ToTaggedSingletonConstructor(constructor interface{}) Sing
 	return nil
 }
 
+func (n *noOpBuilder) ToFactory(constructor interface{}) {}
+
 type baseBuilder struct {
 	module      *module
 	bindingKeys []bindingKey
+	// nil if not scoped
+	scope       Scope
+	onConstruct []onConstructHook
 }
 
 func newBuilder(module *module, bindingKeys []bindingKey) InterfaceBuilder {
-	return &baseBuilder{module, bindingKeys}
+	return &baseBuilder{module, bindingKeys, nil, nil}
+}
+
+func (b *baseBuilder) In(scope Scope) Builder {
+	if scope == nil {
+		b.module.addBindingError(ErrNil)
+		return newNoOpBuilder()
+	}
+	return &baseBuilder{b.module, b.bindingKeys, scope, b.onConstruct}
+}
+
+func (b *baseBuilder) OnConstruct(fn interface{}) Builder {
+	hook, err := newOnConstructHook(b.bindingKeys, fn)
+	if err != nil {
+		if injectErr, ok := err.(*Error); ok && injectErr.Key.Type == nil {
+			err = injectErr.withBindingKey(b.bindingKeys[0])
+		}
+		b.module.addBindingError(err)
+		return newNoOpBuilder()
+	}
+	onConstruct := append(append([]onConstructHook(nil), b.onConstruct...), hook)
+	return &baseBuilder{b.module, b.bindingKeys, b.scope, onConstruct}
 }
 
 func (b *baseBuilder) To(to interface{}) {
-	b.to(to, verifyBindingReflectType, newIntermediateBinding)
+	b.verifyNotScoped()
+	b.to(to, verifyIntermediateReflectType, newIntermediateBinding)
+}
+
+func (b *baseBuilder) ToTagged(tag string, to interface{}) {
+	b.verifyNotScoped()
+	if !b.module.verifyTag(tag) {
+		return
+	}
+	b.to(to, verifyIntermediateReflectType, newTaggedIntermediateBinding(tag))
 }
 
 func (b *baseBuilder) ToSingleton(singleton interface{}) {
+	b.verifyNotScoped()
+	b.verifyNoOnConstruct()
 	b.to(singleton, verifyBindingReflectType, newSingletonBinding)
 }
 
 func (b *baseBuilder) ToConstructor(constructor interface{}) {
-	b.to(constructor, verifyConstructorReflectType, newConstructorBinding)
+	b.to(constructor, verifyConstructorReflectType, b.scoped(b.withOnConstruct(newConstructorBinding)))
 }
 
 func (b *baseBuilder) ToSingletonConstructor(constructor interface{}) SingletonBuilder {
-	b.to(constructor, verifyConstructorReflectType, newSingletonConstructorBinding)
-	return newSingletonBuilder(b.module, b.bindingKeys[0].reflectType())
+	b.verifyNotScoped()
+	b.to(constructor, verifyConstructorReflectType, b.withOnConstruct(newSingletonConstructorBinding))
+	return newSingletonBuilder(b.module, b.bindingKeys)
 }
 
 func (b *baseBuilder) ToTaggedConstructor(constructor interface{}) {
-	b.to(constructor, verifyTaggedConstructorReflectType, newTaggedConstructorBinding)
+	b.to(constructor, verifyTaggedConstructorReflectType, b.scoped(b.withOnConstruct(newTaggedConstructorBinding)))
 }
 
 func (b *baseBuilder) ToTaggedSingletonConstructor(constructor interface{}) SingletonBuilder {
-	b.to(constructor, verifyTaggedConstructorReflectType, newTaggedSingletonConstructorBinding)
-	return newSingletonBuilder(b.module, b.bindingKeys[0].reflectType())
+	b.verifyNotScoped()
+	b.to(constructor, verifyTaggedConstructorReflectType, b.withOnConstruct(newTaggedSingletonConstructorBinding))
+	return newSingletonBuilder(b.module, b.bindingKeys)
 }
 
-func (b *baseBuilder) to(object interface{}, verifyFunc func(reflect.Type, reflect.Type) error, newBindingFunc func(interface{}) binding) {
-	objectReflectType := reflect.TypeOf(object)
+func (b *baseBuilder) ToFactory(constructor interface{}) {
+	b.verifyNoOnConstruct()
+	if !b.verify(constructor, verifyFactoryReflectType) {
+		return
+	}
+	// the binding makes a function of the factory type, so every binding key
+	// gets its own binding
 	for _, bindingKey := range b.bindingKeys {
-		if err := verifyFunc(bindingKey.reflectType(), objectReflectType); err != nil {
-			b.module.addBindingError(err)
-			return
+		b.setBinding(bindingKey, b.scoped(newFactoryBinding(bindingKey.reflectType()))(constructor))
+	}
+}
+
+// scoped wraps the bindings created by newBindingFunc in the scope of the builder, if any
+func (b *baseBuilder) scoped(newBindingFunc func(interface{}) binding) func(interface{}) binding {
+	if b.scope == nil {
+		return newBindingFunc
+	}
+	return func(object interface{}) binding {
+		return newScopedBinding(newBindingFunc(object), b.scope)
+	}
+}
+
+// withOnConstruct sets the OnConstruct hooks of the builder, if any, on the
+// constructor bindings created by newBindingFunc
+func (b *baseBuilder) withOnConstruct(newBindingFunc func(interface{}) binding) func(interface{}) binding {
+	if len(b.onConstruct) == 0 {
+		return newBindingFunc
+	}
+	return func(object interface{}) binding {
+		binding := newBindingFunc(object)
+		switch binding := binding.(type) {
+		case *constructorBinding:
+			binding.onConstruct = b.onConstruct
+		case *singletonConstructorBinding:
+			binding.onConstruct = b.onConstruct
+		case *taggedConstructorBinding:
+			binding.onConstruct = b.onConstruct
+		case *taggedSingletonConstructorBinding:
+			binding.onConstruct = b.onConstruct
 		}
+		return binding
+	}
+}
+
+func (b *baseBuilder) verifyNoOnConstruct() {
+	if len(b.onConstruct) > 0 {
+		b.module.addBindingError(ErrOnConstructNotSupported.withBindingKey(b.bindingKeys[0]))
+	}
+}
+
+func (b *baseBuilder) verifyNotScoped() {
+	if b.scope != nil {
+		b.module.addBindingError(ErrScopeNotSupported.withBindingKey(b.bindingKeys[0]))
+	}
+}
+
+func (b *baseBuilder) to(object interface{}, verifyFunc func(reflect.Type, reflect.Type) error, newBindingFunc func(interface{}) binding) {
+	if !b.verify(object, verifyFunc) {
+		return
 	}
 	binding := newBindingFunc(object)
 	for _, bindingKey := range b.bindingKeys {
@@ -79,14 +180,31 @@ func (b *baseBuilder) to(object interface{}, verifyFunc func(reflect.Type, refle
 	}
 }
 
+// verify adds a binding error and returns false if object cannot be bound to
+// all binding keys of the builder
+func (b *baseBuilder) verify(object interface{}, verifyFunc func(reflect.Type, reflect.Type) error) bool {
+	objectReflectType := reflect.TypeOf(object)
+	for _, bindingKey := range b.bindingKeys {
+		if err := verifyFunc(bindingKey.reflectType(), objectReflectType); err != nil {
+			if injectErr, ok := err.(*Error); ok && injectErr.Key.Type == nil {
+				err = injectErr.withBindingKey(bindingKey)
+			}
+			b.module.addBindingError(err)
+			return false
+		}
+	}
+	return true
+}
+
 func (b *baseBuilder) setBinding(bindingKey bindingKey, binding binding) {
 	b.module.setBinding(bindingKey, binding)
 }
 
 type singletonBuilder struct {
 	module *module
-	t      reflect.Type
-	fn     interface{}
+	// all binding keys of the singleton, which are constructed eagerly
+	bindingKeys []bindingKey
+	fn          interface{}
 }
 
 func (b *singletonBuilder) Eagerly() {
@@ -104,19 +222,28 @@ func (b *singletonBuilder) EagerlyAndCall(function interface{}) {
 	b.module.eager = append(b.module.eager, b)
 }
 
-func newSingletonBuilder(module *module, t reflect.Type) SingletonBuilder {
-	return &singletonBuilder{module: module, t: t}
+func newSingletonBuilder(module *module, bindingKeys []bindingKey) SingletonBuilder {
+	return &singletonBuilder{module: module, bindingKeys: bindingKeys}
+}
+
+// verifyIntermediateReflectType allows linking to the binding of another interface,
+// given as a pointer to the interface, that is assignable to the interface
+func verifyIntermediateReflectType(bindingKeyReflectType reflect.Type, toReflectType reflect.Type) error {
+	if isInterfacePtr(toReflectType) {
+		toReflectType = toReflectType.Elem()
+	}
+	return verifyBindingReflectType(bindingKeyReflectType, toReflectType)
 }
 
 func verifyBindingReflectType(bindingKeyReflectType reflect.Type, bindingReflectType reflect.Type) error {
 	if !isSupportedBindingKeyReflectType(bindingKeyReflectType) {
-		return errNotSupportedYet.withTag("bindingKeyReflectType", bindingReflectType)
+		return ErrNotSupportedYet.withTag("bindingKeyReflectType", bindingReflectType)
 	}
 	if isInterfacePtr(bindingKeyReflectType) {
 		bindingKeyReflectType = bindingKeyReflectType.Elem()
 	}
 	if !bindingReflectType.AssignableTo(bindingKeyReflectType) {
-		return errNotAssignable.withTag("bindingKeyReflectType", bindingKeyReflectType).withTag("bindingReflectType", bindingReflectType)
+		return ErrNotAssignable.withTag("bindingKeyReflectType", bindingKeyReflectType).withTag("bindingReflectType", bindingReflectType)
 	}
 	return nil
 }
@@ -128,8 +255,10 @@ func verifyConstructorReflectType(bindingKeyReflectType reflect.Type, constructo
 	return verifyConstructorReturnValues(bindingKeyReflectType, constructorReflectType)
 }
 
+// verifyTaggedConstructorReflectType does not verify the fields of the struct
+// parameter, which are verified when the binding is installed to an injector
 func verifyTaggedConstructorReflectType(bindingKeyReflectType reflect.Type, constructorReflectType reflect.Type) error {
-	if err := verifyIsTaggedFunc(constructorReflectType); err != nil {
+	if err := verifyTaggedFuncParameters(constructorReflectType); err != nil {
 		return err
 	}
 	return verifyConstructorReturnValues(bindingKeyReflectType, constructorReflectType)
@@ -138,7 +267,7 @@ func verifyTaggedConstructorReflectType(bindingKeyReflectType reflect.Type, cons
 func verifyConstructorReturnValues(bindingKeyReflectType reflect.Type, constructorReflectType reflect.Type) error {
 	numOut := constructorReflectType.NumOut()
 	if numOut < 1 || numOut > 2 {
-		return errConstructorReturnValuesInvalid.withTag("constructorReflectType", constructorReflectType)
+		return ErrConstructorReturnValuesInvalid.withTag("constructorReflectType", constructorReflectType)
 	}
 	if bindingKeyReflectType != nil {
 		if err := verifyBindingReflectType(bindingKeyReflectType, constructorReflectType.Out(0)); err != nil {
@@ -146,7 +275,7 @@ func verifyConstructorReturnValues(bindingKeyReflectType reflect.Type, construct
 		}
 	}
 	if numOut == 2 && !constructorReflectType.Out(1).AssignableTo(errorReflectType) {
-		return errConstructorReturnValuesInvalid.withTag("constructorReflectType", constructorReflectType)
+		return ErrConstructorReturnValuesInvalid.withTag("constructorReflectType", constructorReflectType)
 	}
 	return nil
 }
@@ -1,13 +1,20 @@
 package inject
 
 import (
+	"context"
 	"reflect"
+	"time"
 )
 
 const (
+	// the default struct tag key, see Options.TagKey
 	taggedFuncStructFieldTag = "inject"
 )
 
+var (
+	contextReflectType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
 // whitelisting types to make sure the framework works
 func isSupportedBindingKeyReflectType(reflectType reflect.Type) bool {
 	return isSupportedBindReflectType(reflectType) || isSupportedBindInterfaceReflectType(reflectType) || isSupportedBindConstantReflectType(reflectType)
@@ -50,6 +57,8 @@ func isSupportedBindReflectType(reflectType reflect.Type) bool {
 		reflect.Complex64,
 		reflect.Complex128:
 		return true
+	case reflect.Func:
+		return isFactory(reflectType)
 	default:
 		return false
 	}
@@ -69,50 +78,64 @@ func isSupportedBindInterfaceReflectType(reflectType reflect.Type) bool {
 	}
 }
 
+// isSupportedBindConstantReflectType returns true for the types of the constant
+// kinds and for named types whose kind is the kind of a primitive constant kind
 func isSupportedBindConstantReflectType(reflectType reflect.Type) bool {
-	_, ok := constantKindForReflectType(reflectType)
+	if _, ok := constantKindForReflectType(reflectType); ok {
+		return true
+	}
+	_, ok := constantKindForReflectKind(reflectType.Kind())
 	return ok
 }
 
 func verifyIsFunc(funcReflectType reflect.Type) error {
 	if !isFunc(funcReflectType) {
-		return errNotFunction.withTag("funcReflectType", funcReflectType)
+		return ErrNotFunction.withTag("funcReflectType", funcReflectType)
 	}
 	numIn := funcReflectType.NumIn()
 	for i := 0; i < numIn; i++ {
-		parameterReflectType := funcReflectType.In(i)
-		if isInterface(parameterReflectType) {
-			parameterReflectType = reflect.PtrTo(parameterReflectType)
-		}
-		if err := verifyParameterCanBeInjected(parameterReflectType, ""); err != nil {
+		if err := verifyDependencyCanBeInjected(newDependency(funcReflectType.In(i), "")); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func verifyIsTaggedFunc(funcReflectType reflect.Type) error {
+func verifyIsTaggedFunc(funcReflectType reflect.Type, tagKey string) error {
+	if err := verifyTaggedFuncParameters(funcReflectType); err != nil {
+		return err
+	}
+	return verifyStructCanBePopulated(funcReflectType.In(0), tagKey)
+}
+
+// verifyTaggedFuncParameters verifies that the function takes one anonymous struct,
+// without verifying the fields, which depend on the struct tag key of the injector
+func verifyTaggedFuncParameters(funcReflectType reflect.Type) error {
 	if !isFunc(funcReflectType) {
-		return errNotFunction.withTag("funcReflectType", funcReflectType)
+		return ErrNotFunction.withTag("funcReflectType", funcReflectType)
 	}
 	if funcReflectType.NumIn() != 1 {
-		return errTaggedParametersInvalid.withTag("funcReflectType", funcReflectType)
+		return ErrTaggedParametersInvalid.withTag("funcReflectType", funcReflectType)
 	}
 	inReflectType := funcReflectType.In(0)
 	if !isStruct(inReflectType) {
-		return errTaggedParametersInvalid.withTag("funcReflectType", funcReflectType)
+		return ErrTaggedParametersInvalid.withTag("funcReflectType", funcReflectType)
 	}
 	if inReflectType.Name() != "" {
-		return errTaggedParametersInvalid.withTag("funcReflectType", funcReflectType)
+		return ErrTaggedParametersInvalid.withTag("funcReflectType", funcReflectType)
 	}
-	return verifyStructCanBePopulated(inReflectType)
+	return nil
 }
 
-func verifyStructCanBePopulated(structReflectType reflect.Type) error {
+func verifyStructCanBePopulated(structReflectType reflect.Type, tagKey string) error {
 	numFields := structReflectType.NumField()
 	for i := 0; i < numFields; i++ {
-		structFieldReflectType, tag := getStructFieldReflectTypeAndTag(structReflectType.Field(i))
-		if err := verifyParameterCanBeInjected(structFieldReflectType, tag); err != nil {
+		structField := structReflectType.Field(i)
+		tag := structField.Tag.Get(tagKey)
+		if err := verifyTag(tag); err != nil {
+			return err
+		}
+		if err := verifyDependencyCanBeInjected(newDependency(structField.Type, tag)); err != nil {
 			return err
 		}
 	}
@@ -121,51 +144,89 @@ func verifyStructCanBePopulated(structReflectType reflect.Type) error {
 
 func verifyParameterCanBeInjected(parameterReflectType reflect.Type, tag string) error {
 	if tag == "" && !isSupportedNoTagParameterReflectType(parameterReflectType) {
-		return errNotSupportedYet.withTag("parameterReflectType", parameterReflectType)
+		return ErrNotSupportedYet.withTag("parameterReflectType", parameterReflectType)
 	}
 	if tag != "" && !isSupportedBindingKeyReflectType(parameterReflectType) {
-		return errNotSupportedYet.withTag("parameterReflectType", parameterReflectType)
+		return ErrNotSupportedYet.withTag("parameterReflectType", parameterReflectType)
 	}
 	return nil
 }
 
-func getParameterBindingKeysForFunc(funcReflectType reflect.Type) []bindingKey {
+// getParameterDependenciesForFunc does not return a dependency for a leading
+// context.Context parameter, see isContextFunc
+func getParameterDependenciesForFunc(funcReflectType reflect.Type) []dependency {
 	numIn := funcReflectType.NumIn()
-	bindingKeys := make([]bindingKey, numIn)
-	for i := 0; i < numIn; i++ {
-		inReflectType := funcReflectType.In(i)
-		if inReflectType.Kind() == reflect.Interface {
-			inReflectType = reflect.PtrTo(inReflectType)
-		}
-		bindingKeys[i] = newBindingKey(inReflectType)
+	start := 0
+	if isContextFunc(funcReflectType) {
+		start = 1
 	}
-	return bindingKeys
+	dependencies := make([]dependency, 0, numIn-start)
+	for i := start; i < numIn; i++ {
+		dependencies = append(dependencies, newDependency(funcReflectType.In(i), ""))
+	}
+	return dependencies
 }
 
-func getParameterBindingKeysForTaggedFunc(funcReflectType reflect.Type) []bindingKey {
-	return getStructFieldBindingKeys(funcReflectType.In(0))
+// isContextFunc returns true if the first parameter of the function is a context.Context,
+// in which case the context of the caller is passed instead of an injected value
+func isContextFunc(funcReflectType reflect.Type) bool {
+	return funcReflectType.NumIn() > 0 && funcReflectType.In(0) == contextReflectType
 }
 
-func getStructFieldBindingKeys(structReflectType reflect.Type) []bindingKey {
-	numFields := structReflectType.NumField()
-	bindingKeys := make([]bindingKey, numFields)
-	for i := 0; i < numFields; i++ {
-		structFieldReflectType, tag := getStructFieldReflectTypeAndTag(structReflectType.Field(i))
-		if tag != "" {
-			bindingKeys[i] = newTaggedBindingKey(structFieldReflectType, tag)
-		} else {
-			bindingKeys[i] = newBindingKey(structFieldReflectType)
-		}
+func prependContextReflectValue(ctx context.Context, reflectValues []reflect.Value) []reflect.Value {
+	return append([]reflect.Value{reflect.ValueOf(&ctx).Elem()}, reflectValues...)
+}
+
+// detachedContext has the values of its parent, but is never canceled and has
+// no deadline, so that a singleton does not fail for good because the context
+// of whoever first needs it is canceled
+type detachedContext struct {
+	parent context.Context
+}
+
+// constructInjectorContextKey marks the context of the singletons constructed by
+// NewInjectorContext, which is not detached, as a failed construction discards
+// the injector anyway
+type constructInjectorContextKey struct{}
+
+func detachContext(ctx context.Context) context.Context {
+	if _, ok := ctx.(detachedContext); ok {
+		return ctx
 	}
-	return bindingKeys
+	if ctx.Value(constructInjectorContextKey{}) != nil {
+		return ctx
+	}
+	return detachedContext{ctx}
+}
+
+func (d detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
 }
 
-func getStructFieldReflectTypeAndTag(structField reflect.StructField) (reflect.Type, string) {
-	structFieldReflectType := structField.Type
-	if structFieldReflectType.Kind() == reflect.Interface {
-		structFieldReflectType = reflect.PtrTo(structFieldReflectType)
+func (d detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (d detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+func getParameterDependenciesForTaggedFunc(funcReflectType reflect.Type, tagKey string) []dependency {
+	return getStructFieldDependencies(funcReflectType.In(0), tagKey)
+}
+
+func getStructFieldDependencies(structReflectType reflect.Type, tagKey string) []dependency {
+	numFields := structReflectType.NumField()
+	dependencies := make([]dependency, numFields)
+	for i := 0; i < numFields; i++ {
+		structField := structReflectType.Field(i)
+		dependencies[i] = newDependency(structField.Type, structField.Tag.Get(tagKey))
 	}
-	return structFieldReflectType, structField.Tag.Get(taggedFuncStructFieldTag)
+	return dependencies
 }
 
 func getTaggedFuncStructReflectValue(structReflectType reflect.Type, reflectValues []reflect.Value) *reflect.Value {
@@ -185,6 +246,42 @@ func populateStructReflectValue(structReflectValue *reflect.Value, reflectValues
 	}
 }
 
+// reflectTypeOf returns the reflect.Type of from, or from itself if it is already a reflect.Type
+func reflectTypeOf(from interface{}) reflect.Type {
+	if fromReflectType, ok := from.(reflect.Type); ok {
+		return fromReflectType
+	}
+	return reflect.TypeOf(from)
+}
+
+// elementReflectType returns the type of values for the given binding key type,
+// which is the interface itself for pointers to interfaces
+func elementReflectType(bindingKeyReflectType reflect.Type) reflect.Type {
+	if isInterfacePtr(bindingKeyReflectType) {
+		return bindingKeyReflectType.Elem()
+	}
+	return bindingKeyReflectType
+}
+
+// bindingKeyReflectTypeOf returns the type to bind values of valueReflectType
+// to, which is the pointer to the interface for interfaces, as for the return
+// values of constructors bound with Module.BindConstructor
+func bindingKeyReflectTypeOf(valueReflectType reflect.Type) reflect.Type {
+	if isInterface(valueReflectType) {
+		return reflect.PtrTo(valueReflectType)
+	}
+	return valueReflectType
+}
+
+// reflectValueOf returns the reflect.Value of value, or the zero value of
+// reflectType if value is nil
+func reflectValueOf(value interface{}, reflectType reflect.Type) reflect.Value {
+	if value == nil {
+		return reflect.Zero(reflectType)
+	}
+	return reflect.ValueOf(value)
+}
+
 func isInterfacePtr(reflectType reflect.Type) bool {
 	return isPtr(reflectType) && isInterface(reflectType.Elem())
 }
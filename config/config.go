@@ -0,0 +1,165 @@
+/*
+Package config builds modules of tagged constant bindings from configuration
+sources such as environment variables, flags, JSON files and .properties files.
+
+Every Key is bound with its name as the tag, parsed as the kind of the Key:
+
+	module := config.NewModule(
+		config.Env("APP_"),
+		config.String("db.host"),
+		config.Uint16("db.port"),
+		config.Bool("debug"),
+	)
+
+	func newDB(str struct {
+		Host string `inject:"db.host"`
+		Port uint16 `inject:"db.port"`
+	}) (*DB, error) { ... }
+
+Keys that are not set in the source are not bound, so they can be injected as
+optional dependencies. Values that cannot be parsed are binding errors returned
+by inject.NewInjector.
+*/
+package config // import "go.pedge.io/inject/config"
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.pedge.io/inject"
+)
+
+// Key is a configuration key that is bound as a tagged constant.
+type Key struct {
+	name string
+	// a value of the type of the constant
+	from interface{}
+}
+
+// Bool returns a Key bound with inject.Module.BindTaggedBool. The functions
+// below return the keys of the other constant kinds of inject.Module. Integers
+// are parsed as decimal numbers, so that "010" is 10.
+func Bool(name string) Key       { return Key{name, false} }
+func Int(name string) Key        { return Key{name, int(0)} }
+func Int8(name string) Key       { return Key{name, int8(0)} }
+func Int16(name string) Key      { return Key{name, int16(0)} }
+func Int32(name string) Key      { return Key{name, int32(0)} }
+func Int64(name string) Key      { return Key{name, int64(0)} }
+func Uint(name string) Key       { return Key{name, uint(0)} }
+func Uint8(name string) Key      { return Key{name, uint8(0)} }
+func Uint16(name string) Key     { return Key{name, uint16(0)} }
+func Uint32(name string) Key     { return Key{name, uint32(0)} }
+func Uint64(name string) Key     { return Key{name, uint64(0)} }
+func Float32(name string) Key    { return Key{name, float32(0)} }
+func Float64(name string) Key    { return Key{name, float64(0)} }
+func Complex64(name string) Key  { return Key{name, complex64(0)} }
+func Complex128(name string) Key { return Key{name, complex128(0)} }
+func String(name string) Key     { return Key{name, ""} }
+
+// Duration returns a Key parsed with time.ParseDuration.
+func Duration(name string) Key { return Key{name, time.Duration(0)} }
+
+// Time returns a Key parsed in the time.RFC3339 format.
+func Time(name string) Key { return Key{name, time.Time{}} }
+
+// Strings returns a Key whose value is split at commas, with surrounding
+// whitespace removed from every element. JSON arrays are joined with commas.
+func Strings(name string) Key { return Key{name, []string(nil)} }
+
+// URL returns a Key parsed with url.Parse.
+func URL(name string) Key { return Key{name, (*url.URL)(nil)} }
+
+// IP returns a Key parsed with net.ParseIP.
+func IP(name string) Key { return Key{name, net.IP(nil)} }
+
+// Constant returns a Key bound with inject.Module.BindTaggedConstant, where from
+// is a value of a named type whose underlying type is a bool, number or string,
+// such as type Port int.
+func Constant(name string, from interface{}) Key { return Key{name, from} }
+
+// NewModule creates a Module that binds every key that is set in the source.
+func NewModule(source Source, keys ...Key) inject.Module {
+	module := inject.NewModule()
+	for _, key := range keys {
+		if key.from == nil {
+			module.AddError(inject.ErrNil)
+			continue
+		}
+		value, ok := source.Lookup(key.name)
+		if !ok {
+			continue
+		}
+		reflectType := reflect.TypeOf(key.from)
+		constant, err := parse(value, reflectType)
+		if err != nil {
+			module.AddError(fmt.Errorf("config: cannot parse value %q of key %q as %s: %w", value, key.name, reflectType, err))
+			continue
+		}
+		module.BindTaggedConstant(key.name, key.from).ToSingleton(constant)
+	}
+	return module
+}
+
+var (
+	durationReflectType = reflect.TypeOf(time.Duration(0))
+	timeReflectType     = reflect.TypeOf(time.Time{})
+	stringsReflectType  = reflect.TypeOf([]string(nil))
+	urlReflectType      = reflect.TypeOf((*url.URL)(nil))
+	ipReflectType       = reflect.TypeOf(net.IP(nil))
+)
+
+// parse returns the value as a value of reflectType
+func parse(value string, reflectType reflect.Type) (interface{}, error) {
+	switch reflectType {
+	case durationReflectType:
+		return time.ParseDuration(value)
+	case timeReflectType:
+		return time.Parse(time.RFC3339, value)
+	case stringsReflectType:
+		if strings.TrimSpace(value) == "" {
+			return []string{}, nil
+		}
+		elements := strings.Split(value, ",")
+		for i, element := range elements {
+			elements[i] = strings.TrimSpace(element)
+		}
+		return elements, nil
+	case urlReflectType:
+		return url.Parse(value)
+	case ipReflectType:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, errors.New("invalid IP address")
+		}
+		return ip, nil
+	}
+	var constant interface{}
+	var err error
+	switch reflectType.Kind() {
+	case reflect.Bool:
+		constant, err = strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		constant, err = strconv.ParseInt(value, 10, reflectType.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		constant, err = strconv.ParseUint(value, 10, reflectType.Bits())
+	case reflect.Float32, reflect.Float64:
+		constant, err = strconv.ParseFloat(value, reflectType.Bits())
+	case reflect.Complex64, reflect.Complex128:
+		constant, err = strconv.ParseComplex(value, reflectType.Bits())
+	case reflect.String:
+		constant = value
+	default:
+		return nil, errors.New("type not supported")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// convert to the exact type, such as int8 or a named type
+	return reflect.ValueOf(constant).Convert(reflectType).Interface(), nil
+}
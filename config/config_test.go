@@ -0,0 +1,263 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.pedge.io/inject"
+)
+
+func TestNewModuleAllKinds(t *testing.T) {
+	source, err := JSON(strings.NewReader(`{
+		"bool": true,
+		"int": -1,
+		"int8": -8,
+		"int16": -16,
+		"int32": "32",
+		"int64": -64,
+		"uint": 1,
+		"uint8": 8,
+		"uint16": 16,
+		"uint32": 32,
+		"uint64": 64,
+		"float32": 3.5,
+		"float64": 6.25,
+		"complex64": "1+2i",
+		"complex128": "3+4i",
+		"string": "hello"
+	}`))
+	require.NoError(t, err)
+	injector, err := inject.NewInjector(NewModule(
+		source,
+		Bool("bool"),
+		Int("int"),
+		Int8("int8"),
+		Int16("int16"),
+		Int32("int32"),
+		Int64("int64"),
+		Uint("uint"),
+		Uint8("uint8"),
+		Uint16("uint16"),
+		Uint32("uint32"),
+		Uint64("uint64"),
+		Float32("float32"),
+		Float64("float64"),
+		Complex64("complex64"),
+		Complex128("complex128"),
+		String("string"),
+	))
+	require.NoError(t, err)
+	for _, expected := range []struct {
+		tag   string
+		get   func(string) (interface{}, error)
+		value interface{}
+	}{
+		{"bool", func(tag string) (interface{}, error) { return injector.GetTaggedBool(tag) }, true},
+		{"int", func(tag string) (interface{}, error) { return injector.GetTaggedInt(tag) }, -1},
+		{"int8", func(tag string) (interface{}, error) { return injector.GetTaggedInt8(tag) }, int8(-8)},
+		{"int16", func(tag string) (interface{}, error) { return injector.GetTaggedInt16(tag) }, int16(-16)},
+		{"int32", func(tag string) (interface{}, error) { return injector.GetTaggedInt32(tag) }, int32(32)},
+		{"int64", func(tag string) (interface{}, error) { return injector.GetTaggedInt64(tag) }, int64(-64)},
+		{"uint", func(tag string) (interface{}, error) { return injector.GetTaggedUint(tag) }, uint(1)},
+		{"uint8", func(tag string) (interface{}, error) { return injector.GetTaggedUint8(tag) }, uint8(8)},
+		{"uint16", func(tag string) (interface{}, error) { return injector.GetTaggedUint16(tag) }, uint16(16)},
+		{"uint32", func(tag string) (interface{}, error) { return injector.GetTaggedUint32(tag) }, uint32(32)},
+		{"uint64", func(tag string) (interface{}, error) { return injector.GetTaggedUint64(tag) }, uint64(64)},
+		{"float32", func(tag string) (interface{}, error) { return injector.GetTaggedFloat32(tag) }, float32(3.5)},
+		{"float64", func(tag string) (interface{}, error) { return injector.GetTaggedFloat64(tag) }, 6.25},
+		{"complex64", func(tag string) (interface{}, error) { return injector.GetTaggedComplex64(tag) }, complex64(1 + 2i)},
+		{"complex128", func(tag string) (interface{}, error) { return injector.GetTaggedComplex128(tag) }, 3 + 4i},
+		{"string", func(tag string) (interface{}, error) { return injector.GetTaggedString(tag) }, "hello"},
+	} {
+		value, err := expected.get(expected.tag)
+		require.NoError(t, err, expected.tag)
+		require.Equal(t, expected.value, value, expected.tag)
+	}
+}
+
+type Port int
+
+func TestNewModuleRichKinds(t *testing.T) {
+	source, err := JSON(strings.NewReader(`{
+		"port": 8080,
+		"timeout": "1m30s",
+		"started": "2020-01-02T03:04:05Z",
+		"hosts": ["one", "two"],
+		"url": "https://example.com/path",
+		"ip": "10.0.0.1"
+	}`))
+	require.NoError(t, err)
+	injector, err := inject.NewInjector(NewModule(
+		source,
+		Constant("port", Port(0)),
+		Duration("timeout"),
+		Time("started"),
+		Strings("hosts"),
+		URL("url"),
+		IP("ip"),
+	))
+	require.NoError(t, err)
+	port, err := injector.GetTaggedConstant("port", Port(0))
+	require.NoError(t, err)
+	require.Equal(t, Port(8080), port)
+	timeout, err := injector.GetTaggedDuration("timeout")
+	require.NoError(t, err)
+	require.Equal(t, 90*time.Second, timeout)
+	started, err := injector.GetTaggedTime("started")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), started)
+	hosts, err := injector.GetTaggedStrings("hosts")
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "two"}, hosts)
+	u, err := injector.GetTaggedURL("url")
+	require.NoError(t, err)
+	require.Equal(t, "/path", u.Path)
+	ip, err := injector.GetTaggedIP("ip")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1", ip.String())
+}
+
+func TestStrings(t *testing.T) {
+	source, err := Properties(strings.NewReader("hosts = one, two ,three\nempty =\n"))
+	require.NoError(t, err)
+	injector, err := inject.NewInjector(NewModule(source, Strings("hosts"), Strings("empty")))
+	require.NoError(t, err)
+	hosts, err := injector.GetTaggedStrings("hosts")
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "two", "three"}, hosts)
+	empty, err := injector.GetTaggedStrings("empty")
+	require.NoError(t, err)
+	require.Empty(t, empty)
+}
+
+func TestNewModuleDecimal(t *testing.T) {
+	source, err := Properties(strings.NewReader("port=010\nother.port=08080\nhex=0x20\n"))
+	require.NoError(t, err)
+	injector, err := inject.NewInjector(NewModule(source, Int("port"), Uint16("other.port")))
+	require.NoError(t, err)
+	port, err := injector.GetTaggedInt("port")
+	require.NoError(t, err)
+	require.Equal(t, 10, port)
+	otherPort, err := injector.GetTaggedUint16("other.port")
+	require.NoError(t, err)
+	require.Equal(t, uint16(8080), otherPort)
+	_, err = inject.NewInjector(NewModule(source, Int32("hex")))
+	require.Error(t, err)
+}
+
+func TestNewModuleParseErrors(t *testing.T) {
+	source, err := JSON(strings.NewReader(`{"port": 70000, "debug": "maybe"}`))
+	require.NoError(t, err)
+	_, err = inject.NewInjector(NewModule(source, Uint16("port"), Bool("debug"), IP("port"), Duration("debug")))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `config: cannot parse value "70000" of key "port" as net.IP`)
+	require.Contains(t, err.Error(), `config: cannot parse value "maybe" of key "debug" as time.Duration`)
+	require.Contains(t, err.Error(), `config: cannot parse value "70000" of key "port" as uint16`)
+	require.Contains(t, err.Error(), `config: cannot parse value "maybe" of key "debug" as bool`)
+}
+
+func TestNewModuleNilConstant(t *testing.T) {
+	source, err := JSON(strings.NewReader(`{"x": "1"}`))
+	require.NoError(t, err)
+	_, err = inject.NewInjector(NewModule(source, Constant("x", nil)))
+	require.True(t, errors.Is(err, inject.ErrNil))
+}
+
+func TestNewModuleKeyNotSet(t *testing.T) {
+	module := NewModule(Env("INJECT_CONFIG_TEST_"), String("not.set"))
+	injector, err := inject.NewInjector(module)
+	require.NoError(t, err)
+	_, err = injector.GetTaggedString("not.set")
+	require.True(t, errors.Is(err, inject.ErrNoBinding))
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("INJECT_CONFIG_TEST_DB_PORT", "5432")
+	value, ok := Env("INJECT_CONFIG_TEST_").Lookup("db.port")
+	require.True(t, ok)
+	require.Equal(t, "5432", value)
+	_, ok = Env("INJECT_CONFIG_TEST_").Lookup("db.host")
+	require.False(t, ok)
+}
+
+func TestFlags(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagSet.Int("port", 8080, "")
+	flagSet.Bool("debug", false, "")
+	require.NoError(t, flagSet.Parse([]string{"-debug"}))
+	injector, err := inject.NewInjector(NewModule(Flags(flagSet), Int("port"), Bool("debug"), String("host")))
+	require.NoError(t, err)
+	port, err := injector.GetTaggedInt("port")
+	require.NoError(t, err)
+	require.Equal(t, 8080, port)
+	debug, err := injector.GetTaggedBool("debug")
+	require.NoError(t, err)
+	require.True(t, debug)
+}
+
+func TestJSONNested(t *testing.T) {
+	source, err := JSON(strings.NewReader(`{"db": {"host": "localhost", "options": {"ssl": false}}, "null": null}`))
+	require.NoError(t, err)
+	value, ok := source.Lookup("db.host")
+	require.True(t, ok)
+	require.Equal(t, "localhost", value)
+	value, ok = source.Lookup("db.options.ssl")
+	require.True(t, ok)
+	require.Equal(t, "false", value)
+	_, ok = source.Lookup("null")
+	require.False(t, ok)
+
+	_, err = JSON(strings.NewReader(`{"db": `))
+	require.Error(t, err)
+}
+
+func TestProperties(t *testing.T) {
+	source, err := Properties(strings.NewReader(`# comment
+! another comment
+
+db.host = localhost
+db.port:5432
+name  hello world
+long = one, \
+       two
+escaped\=key = tab\there é
+`))
+	require.NoError(t, err)
+	for key, expected := range map[string]string{
+		"db.host":     "localhost",
+		"db.port":     "5432",
+		"name":        "hello world",
+		"long":        "one, two",
+		"escaped=key": "tab\there é",
+	} {
+		value, ok := source.Lookup(key)
+		require.True(t, ok, key)
+		require.Equal(t, expected, value, key)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"port": 1}`), 0644))
+	source, err := JSONFile(jsonPath)
+	require.NoError(t, err)
+	value, _ := source.Lookup("port")
+	require.Equal(t, "1", value)
+
+	propertiesPath := filepath.Join(dir, "config.properties")
+	require.NoError(t, os.WriteFile(propertiesPath, []byte("port=2\n"), 0644))
+	source, err = PropertiesFile(propertiesPath)
+	require.NoError(t, err)
+	value, _ = source.Lookup("port")
+	require.Equal(t, "2", value)
+
+	_, err = JSONFile(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
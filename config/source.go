@@ -0,0 +1,233 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Source provides the values of configuration keys.
+type Source interface {
+	// Lookup returns the value of the key, and false if the key is not set.
+	Lookup(key string) (string, bool)
+}
+
+// Env returns a Source of environment variables. The environment variable of a
+// key is the prefix followed by the key in upper case, with all characters
+// other than letters and digits replaced by underscores, so that the key
+// "db.port" with the prefix "APP_" is read from APP_DB_PORT.
+func Env(prefix string) Source {
+	return envSource{prefix}
+}
+
+// Flags returns a Source of the flags of the FlagSet, which must be parsed
+// before the Module is created. Flags that were not set have their default value.
+func Flags(flagSet *flag.FlagSet) Source {
+	return flagsSource{flagSet}
+}
+
+// JSON returns a Source of the values of a JSON object. The keys of nested
+// objects are joined with dots, so that {"db": {"port": 5432}} sets the key
+// "db.port". Arrays are joined with commas, see Strings, and null values are
+// not set.
+func JSON(reader io.Reader) (Source, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("config: cannot decode JSON: %w", err)
+	}
+	values := make(mapSource)
+	addJSONValues(values, "", object)
+	return values, nil
+}
+
+// JSONFile returns a Source of the JSON object in the file, see JSON.
+func JSONFile(path string) (Source, error) {
+	return readFile(path, JSON)
+}
+
+// Properties returns a Source of a .properties file. Keys and values are
+// separated by '=', ':' or whitespace, lines starting with '#' or '!' are
+// comments, and lines ending with a backslash continue on the next line.
+func Properties(reader io.Reader) (Source, error) {
+	values := make(mapSource)
+	scanner := bufio.NewScanner(reader)
+	var logicalLine string
+	for scanner.Scan() {
+		line := strings.TrimLeftFunc(scanner.Text(), unicode.IsSpace)
+		if logicalLine == "" && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+		if isContinued(line) {
+			logicalLine += line[:len(line)-1]
+			continue
+		}
+		logicalLine += line
+		key, value, err := parsePropertiesLine(logicalLine)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+		logicalLine = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("config: cannot read properties: %w", err)
+	}
+	if logicalLine != "" {
+		key, value, err := parsePropertiesLine(logicalLine)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// PropertiesFile returns a Source of the .properties file, see Properties.
+func PropertiesFile(path string) (Source, error) {
+	return readFile(path, Properties)
+}
+
+type envSource struct {
+	prefix string
+}
+
+func (e envSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(e.prefix + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, key))
+}
+
+type flagsSource struct {
+	flagSet *flag.FlagSet
+}
+
+func (f flagsSource) Lookup(key string) (string, bool) {
+	found := f.flagSet.Lookup(key)
+	if found == nil {
+		return "", false
+	}
+	return found.Value.String(), true
+}
+
+type mapSource map[string]string
+
+func (m mapSource) Lookup(key string) (string, bool) {
+	value, ok := m[key]
+	return value, ok
+}
+
+func addJSONValues(values mapSource, prefix string, object map[string]interface{}) {
+	for name, value := range object {
+		key := prefix + name
+		switch value := value.(type) {
+		case map[string]interface{}:
+			addJSONValues(values, key+".", value)
+		case string:
+			values[key] = value
+		case []interface{}:
+			elements := make([]string, 0, len(value))
+			for _, element := range value {
+				if element != nil {
+					elements = append(elements, fmt.Sprint(element))
+				}
+			}
+			values[key] = strings.Join(elements, ",")
+		default:
+			// numbers and bools
+			if value != nil {
+				values[key] = fmt.Sprint(value)
+			}
+		}
+	}
+}
+
+func readFile(path string, newSource func(io.Reader) (Source, error)) (Source, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	defer file.Close()
+	return newSource(file)
+}
+
+// isContinued returns true if the line ends with an odd number of backslashes
+func isContinued(line string) bool {
+	numBackslashes := len(line) - len(strings.TrimRight(line, `\`))
+	return numBackslashes%2 == 1
+}
+
+// parsePropertiesLine splits a logical line into the unescaped key and value
+func parsePropertiesLine(line string) (string, string, error) {
+	keyEnd := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if line[i] == '=' || line[i] == ':' || unicode.IsSpace(rune(line[i])) {
+			keyEnd = i
+			break
+		}
+	}
+	value := strings.TrimLeftFunc(line[keyEnd:], unicode.IsSpace)
+	if value != "" && (value[0] == '=' || value[0] == ':') {
+		value = strings.TrimLeftFunc(value[1:], unicode.IsSpace)
+	}
+	key, err := unescapeProperty(line[:keyEnd])
+	if err != nil {
+		return "", "", err
+	}
+	value, err = unescapeProperty(value)
+	if err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			builder.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("config: invalid unicode escape in property %q", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("config: invalid unicode escape in property %q", s)
+			}
+			builder.WriteRune(rune(r))
+			i += 4
+		default:
+			builder.WriteByte(s[i])
+		}
+	}
+	return builder.String(), nil
+}
@@ -2,7 +2,10 @@ package inject
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"reflect"
+	"time"
 )
 
 const (
@@ -22,6 +25,11 @@ const (
 	complex64ConstantKind
 	complex128ConstantKind
 	stringConstantKind
+	durationConstantKind
+	timeConstantKind
+	stringsConstantKind
+	urlConstantKind
+	ipConstantKind
 
 	boolConstant       = false
 	intConstant        = int(0)
@@ -39,9 +47,15 @@ const (
 	complex64Constant  = complex64(0i)
 	complex128Constant = complex128(0i)
 	stringConstant     = ""
+	durationConstant   = time.Duration(0)
 )
 
 var (
+	timeConstant    = time.Time{}
+	stringsConstant = []string(nil)
+	urlConstant     = (*url.URL)(nil)
+	ipConstant      = net.IP(nil)
+
 	boolReflectType       = reflect.TypeOf(boolConstant)
 	intReflectType        = reflect.TypeOf(intConstant)
 	int8ReflectType       = reflect.TypeOf(int8Constant)
@@ -58,6 +72,11 @@ var (
 	complex64ReflectType  = reflect.TypeOf(complex64Constant)
 	complex128ReflectType = reflect.TypeOf(complex128Constant)
 	stringReflectType     = reflect.TypeOf(stringConstant)
+	durationReflectType   = reflect.TypeOf(durationConstant)
+	timeReflectType       = reflect.TypeOf(timeConstant)
+	stringsReflectType    = reflect.TypeOf(stringsConstant)
+	urlReflectType        = reflect.TypeOf(urlConstant)
+	ipReflectType         = reflect.TypeOf(ipConstant)
 
 	constantKindToReflectKind = map[constantKind]reflect.Kind{
 		boolConstantKind:       reflect.Bool,
@@ -76,6 +95,11 @@ var (
 		complex64ConstantKind:  reflect.Complex64,
 		complex128ConstantKind: reflect.Complex128,
 		stringConstantKind:     reflect.String,
+		durationConstantKind:   reflect.Int64,
+		timeConstantKind:       reflect.Struct,
+		stringsConstantKind:    reflect.Slice,
+		urlConstantKind:        reflect.Ptr,
+		ipConstantKind:         reflect.Slice,
 	}
 	lenConstantKindToReflectKind = len(constantKindToReflectKind)
 
@@ -96,9 +120,16 @@ var (
 		complex64ConstantKind:  complex64ReflectType,
 		complex128ConstantKind: complex128ReflectType,
 		stringConstantKind:     stringReflectType,
+		durationConstantKind:   durationReflectType,
+		timeConstantKind:       timeReflectType,
+		stringsConstantKind:    stringsReflectType,
+		urlConstantKind:        urlReflectType,
+		ipConstantKind:         ipReflectType,
 	}
 	lenConstantKindToReflectType = len(constantKindToReflectType)
 
+	// only the kinds of the primitive constant kinds, named types with these
+	// kinds can be bound with BindTaggedConstant
 	reflectKindToConstantKind = map[reflect.Kind]constantKind{
 		reflect.Bool:       boolConstantKind,
 		reflect.Int:        intConstantKind,
@@ -135,6 +166,11 @@ var (
 		complex64ReflectType:  complex64ConstantKind,
 		complex128ReflectType: complex128ConstantKind,
 		stringReflectType:     stringConstantKind,
+		durationReflectType:   durationConstantKind,
+		timeReflectType:       timeConstantKind,
+		stringsReflectType:    stringsConstantKind,
+		urlReflectType:        urlConstantKind,
+		ipReflectType:         ipConstantKind,
 	}
 
 	constantKindToConstant = map[constantKind]interface{}{
@@ -154,6 +190,11 @@ var (
 		complex64ConstantKind:  complex64Constant,
 		complex128ConstantKind: complex128Constant,
 		stringConstantKind:     stringConstant,
+		durationConstantKind:   durationConstant,
+		timeConstantKind:       timeConstant,
+		stringsConstantKind:    stringsConstant,
+		urlConstantKind:        urlConstant,
+		ipConstantKind:         ipConstant,
 	}
 	lenConstantKindToConstant = len(constantKindToConstant)
 )
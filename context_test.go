@@ -0,0 +1,133 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type contextKey struct{}
+
+type ContextStruct struct {
+	value  string
+	simple SimpleInterface
+}
+
+func createContextStruct(ctx context.Context, simple SimpleInterface) (*ContextStruct, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	value, _ := ctx.Value(contextKey{}).(string)
+	return &ContextStruct{value, simple}, nil
+}
+
+func TestGetContext(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.Bind(&ContextStruct{}).ToConstructor(createContextStruct)
+	module.BindTagged("tagOne", &ContextStruct{}).ToConstructor(createContextStruct)
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), contextKey{}, "value")
+			object, err := injector.GetContext(ctx, &ContextStruct{})
+			require.NoError(t, err)
+			require.Equal(t, "value", object.(*ContextStruct).value)
+			require.Equal(t, "hello", object.(*ContextStruct).simple.Foo())
+
+			object, err = injector.GetTaggedContext(ctx, "tagOne", &ContextStruct{})
+			require.NoError(t, err)
+			require.Equal(t, "value", object.(*ContextStruct).value)
+
+			contextStruct, err := GetContext[*ContextStruct](ctx, injector)
+			require.NoError(t, err)
+			require.Equal(t, "value", contextStruct.value)
+
+			// without a context, the constructor receives context.Background()
+			object, err = injector.Get(&ContextStruct{})
+			require.NoError(t, err)
+			require.Equal(t, "", object.(*ContextStruct).value)
+
+			cancelCtx, cancel := context.WithCancel(ctx)
+			cancel()
+			_, err = injector.GetContext(cancelCtx, &ContextStruct{})
+			require.True(t, errors.Is(err, context.Canceled))
+		})
+	}
+}
+
+func TestCallContext(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.Bind(&ContextStruct{}).ToConstructor(createContextStruct)
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), contextKey{}, "value")
+			values, err := injector.CallContext(ctx, func(ctx context.Context, contextStruct *ContextStruct) string {
+				return ctx.Value(contextKey{}).(string) + "-" + contextStruct.value
+			})
+			require.NoError(t, err)
+			require.Equal(t, "value-value", values[0])
+
+			values, err = injector.CallTaggedContext(ctx, func(s struct{ ContextStruct *ContextStruct }) string {
+				return s.ContextStruct.value
+			})
+			require.NoError(t, err)
+			require.Equal(t, "value", values[0])
+		})
+	}
+}
+
+func TestNewInjectorContextEagerSingleton(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.Bind(&ContextStruct{}).ToSingletonConstructor(createContextStruct).Eagerly()
+	ctx := context.WithValue(context.Background(), contextKey{}, "eager")
+	injector, err := NewInjectorContext(ctx, module)
+	require.NoError(t, err)
+	object, err := injector.Get(&ContextStruct{})
+	require.NoError(t, err)
+	require.Equal(t, "eager", object.(*ContextStruct).value)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = NewInjectorContext(cancelCtx, module)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestNewInjectorContextEagerSingletonDeadline(t *testing.T) {
+	module := NewModule()
+	module.Bind(&ContextStruct{}).ToSingletonConstructor(func(ctx context.Context) (*ContextStruct, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(2 * time.Second):
+			return &ContextStruct{}, nil
+		}
+	}).Eagerly()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := NewInjectorContext(ctx, module)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.True(t, time.Since(start) < time.Second)
+}
+
+func TestSingletonDetachedContext(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.Bind(&ContextStruct{}).ToSingletonConstructor(createContextStruct)
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	// the singleton has the values of the first context, but not its cancellation
+	cancelCtx, cancel := context.WithCancel(context.WithValue(context.Background(), contextKey{}, "value"))
+	cancel()
+	object, err := injector.GetContext(cancelCtx, &ContextStruct{})
+	require.NoError(t, err)
+	require.Equal(t, "value", object.(*ContextStruct).value)
+	object, err = injector.GetContext(context.Background(), &ContextStruct{})
+	require.NoError(t, err)
+	require.Equal(t, "value", object.(*ContextStruct).value)
+}
@@ -0,0 +1,164 @@
+package inject
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+const (
+	optionalTagOption = "optional"
+)
+
+var (
+	optionalValueReflectType = reflect.TypeOf((*optionalValue)(nil)).Elem()
+)
+
+// Provider is a constructor parameter or struct field that resolves T every time
+// it is called, instead of once when it is injected. A parameter of type
+// func() (T, error) is injected the same way.
+//
+// The context used to resolve T is the one the Provider was injected with.
+type Provider[T any] func() (T, error)
+
+// Optional is a constructor parameter or struct field that is injected even
+// if there is no binding for T, in which case Present is false and Value is
+// the zero value of T.
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// optionalValue is implemented by all instantiations of Optional
+type optionalValue interface {
+	bindingReflectType() reflect.Type
+	present(value interface{}) (reflect.Value, error)
+}
+
+func (Optional[T]) bindingReflectType() reflect.Type {
+	return bindingReflectTypeFor[T]()
+}
+
+func (Optional[T]) present(value interface{}) (reflect.Value, error) {
+	v, err := valueFor[T](value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(Optional[T]{v, true}), nil
+}
+
+// dependency is a function parameter or struct field that is injected
+type dependency struct {
+	bindingKey bindingKey
+	// the type of the parameter or field
+	reflectType reflect.Type
+	// whether the zero value is injected if there is no binding
+	optional bool
+	// non-nil if the parameter or field is an Optional
+	optionalValue optionalValue
+	// whether the parameter or field is a Provider, resolved when called
+	provider bool
+	// whether the binding is resolved after the value the dependency is injected
+	// into is constructed, in which case it cannot be part of a dependency cycle
+	lazy bool
+}
+
+// newDependency returns the dependency for a parameter or field of the given type,
+// with the name and options of an inject struct tag if any
+func newDependency(reflectType reflect.Type, tag string) dependency {
+	name, optional := parseTag(tag)
+	dependency := dependency{reflectType: reflectType, optional: optional}
+	bindingKeyReflectType := reflectType
+	if isOptional(reflectType) {
+		dependency.optional = true
+		dependency.optionalValue = reflect.Zero(reflectType).Interface().(optionalValue)
+		bindingKeyReflectType = dependency.optionalValue.bindingReflectType()
+	} else if isProvider(reflectType) {
+		dependency.provider = true
+		dependency.lazy = true
+		bindingKeyReflectType = bindingKeyReflectTypeOf(reflectType.Out(0))
+	} else if isInterface(reflectType) {
+		bindingKeyReflectType = reflect.PtrTo(reflectType)
+	}
+	if name != "" {
+		dependency.bindingKey = newTaggedBindingKey(bindingKeyReflectType, name)
+	} else {
+		dependency.bindingKey = newBindingKey(bindingKeyReflectType)
+	}
+	return dependency
+}
+
+// reflectValue returns the value to inject for the dependency given the
+// value of its binding
+func (d dependency) reflectValue(value interface{}) (reflect.Value, error) {
+	if d.optionalValue != nil {
+		return d.optionalValue.present(value)
+	}
+	return reflectValueOf(value, d.reflectType), nil
+}
+
+// providerReflectValue returns a Provider for the dependency that gets the value
+// of binding with ctx every time it is called. A Provider called while the value
+// it is injected into is constructed continues the resolution r, so that a
+// dependency cycle is an error instead of a deadlock or an endless recursion.
+func (d dependency) providerReflectValue(ctx context.Context, r *resolution, injector *injector, binding resolvedBinding) reflect.Value {
+	valueReflectType := d.reflectType.Out(0)
+	return reflect.MakeFunc(d.reflectType, func([]reflect.Value) []reflect.Value {
+		value, err := injector.resolve(ctx, r.active(), d.bindingKey, binding)
+		if err != nil {
+			return []reflect.Value{reflect.Zero(valueReflectType), reflect.ValueOf(&err).Elem()}
+		}
+		return []reflect.Value{reflectValueOf(value, valueReflectType), reflect.Zero(errorReflectType)}
+	})
+}
+
+// parseTag splits an inject struct tag such as "english,optional" into
+// the tag of the binding and whether the dependency is optional
+func parseTag(tag string) (string, bool) {
+	name, options, _ := strings.Cut(tag, ",")
+	optional := false
+	for _, option := range strings.Split(options, ",") {
+		if option == optionalTagOption {
+			optional = true
+		}
+	}
+	return name, optional
+}
+
+func verifyTag(tag string) error {
+	_, options, ok := strings.Cut(tag, ",")
+	if !ok {
+		return nil
+	}
+	for _, option := range strings.Split(options, ",") {
+		if option != optionalTagOption {
+			return ErrUnknownTagOption.withTag("tag", tag).withTag("option", option)
+		}
+	}
+	return nil
+}
+
+// isProvider returns true for func() (T, error), including Provider[T]
+func isProvider(reflectType reflect.Type) bool {
+	return isFunc(reflectType) &&
+		reflectType.NumIn() == 0 &&
+		reflectType.NumOut() == 2 &&
+		reflectType.Out(1) == errorReflectType
+}
+
+func verifyDependencyCanBeInjected(dependency dependency) error {
+	if dependency.reflectType.Kind() == reflect.Ptr && isOptional(dependency.reflectType.Elem()) {
+		return ErrNotSupportedYet.withTag("parameterReflectType", dependency.reflectType)
+	}
+	// the zero value of a provider is a nil function
+	if dependency.provider && dependency.optional {
+		return ErrNotSupportedYet.withTag("parameterReflectType", dependency.reflectType)
+	}
+	return verifyParameterCanBeInjected(dependency.bindingKey.reflectType(), bindingKeyTag(dependency.bindingKey))
+}
+
+// isOptional returns true for Optional types, but not for pointers to them, whose
+// value methods cannot be called on the nil pointer
+func isOptional(reflectType reflect.Type) bool {
+	return reflectType.Kind() != reflect.Ptr && reflectType.Implements(optionalValueReflectType)
+}
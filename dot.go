@@ -0,0 +1,155 @@
+package inject
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	dotReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// WriteDOT writes the dependency graph of the bindings of the Injector in the
+// Graphviz DOT format.
+//
+// Nodes are binding keys, labelled with their type, tag and kind of binding, and
+// edges point from bindings to the bindings of their dependencies. Edges of lazy
+// dependencies such as providers are dashed, and edges of optional dependencies are
+// dotted. The bindings of every injector, from the root injector down to the
+// given child injector, are drawn in their own cluster.
+func WriteDOT(writer io.Writer, injector Injector) error {
+	castInjector, err := castInjector(injector)
+	if err != nil {
+		return err
+	}
+	var clusters []*dotCluster
+	for i := castInjector; i != nil; i = i.parent {
+		bindings := make(map[bindingKey]describedBinding, len(i.bindings))
+		for bindingKey, resolvedBinding := range i.bindings {
+			bindings[bindingKey] = resolvedBinding
+		}
+		clusters = append([]*dotCluster{{"", bindings}}, clusters...)
+	}
+	for i, cluster := range clusters {
+		if i == 0 {
+			cluster.label = "injector"
+		} else {
+			cluster.label = fmt.Sprintf("child injector %d", i)
+		}
+	}
+	return writeDOT(writer, clusters)
+}
+
+// WriteModuleDOT writes the dependency graph of the bindings of the Module in the
+// Graphviz DOT format, as with WriteDOT. Dependencies that are not bound in the
+// Module are drawn with dashed nodes.
+func WriteModuleDOT(writer io.Writer, m Module) error {
+	// the bindings of a private module are drawn, not those of the module it is
+	// installed in by castModule
+	if privateModule, ok := m.(*privateModule); ok {
+		m = privateModule.module
+	}
+	castModule, ok := castModule(m)
+	if !ok {
+		return ErrCannotCastModule
+	}
+	bindings := make(map[bindingKey]describedBinding, len(castModule.bindings))
+	for bindingKey, binding := range castModule.bindings {
+		bindings[bindingKey] = binding
+	}
+	return writeDOT(writer, []*dotCluster{{"", bindings}})
+}
+
+type dotCluster struct {
+	// empty if the bindings are not drawn in a cluster
+	label    string
+	bindings map[bindingKey]describedBinding
+}
+
+// writeDOT writes the clusters, where the bindings of a cluster can depend on the
+// bindings of the clusters before it
+func writeDOT(writer io.Writer, clusters []*dotCluster) error {
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteString("digraph inject {\n")
+	nodeIDs := make([]map[bindingKey]string, len(clusters))
+	numNodes := 0
+	newNodeID := func() string {
+		numNodes++
+		return fmt.Sprintf("n%d", numNodes)
+	}
+	for i, cluster := range clusters {
+		nodeIDs[i] = make(map[bindingKey]string, len(cluster.bindings))
+		indent := "\t"
+		if cluster.label != "" {
+			fmt.Fprintf(buffer, "\tsubgraph cluster_%d {\n\t\tlabel=%s;\n", i, dotLabel(cluster.label))
+			indent = "\t\t"
+		}
+		for _, bindingKey := range sortedBindingKeys(cluster.bindings) {
+			nodeID := newNodeID()
+			nodeIDs[i][bindingKey] = nodeID
+			fmt.Fprintf(buffer, "%s%s [shape=box, label=%s];\n", indent, nodeID, dotLabel(describedBindingKeyLabels(bindingKey, cluster.bindings[bindingKey].kind())...))
+		}
+		if cluster.label != "" {
+			buffer.WriteString("\t}\n")
+		}
+	}
+	unboundNodeIDs := make(map[bindingKey]string)
+	for i, cluster := range clusters {
+		for _, bindingKey := range sortedBindingKeys(cluster.bindings) {
+			for _, dependency := range cluster.bindings[bindingKey].dependencies() {
+				dependencyNodeID, ok := dotDependencyNodeID(nodeIDs[:i+1], dependency.bindingKey)
+				if !ok {
+					if dependencyNodeID, ok = unboundNodeIDs[dependency.bindingKey]; !ok {
+						dependencyNodeID = newNodeID()
+						unboundNodeIDs[dependency.bindingKey] = dependencyNodeID
+						fmt.Fprintf(buffer, "\t%s [shape=box, style=dashed, label=%s];\n", dependencyNodeID, dotLabel(describedBindingKeyLabels(dependency.bindingKey, "unbound")...))
+					}
+				}
+				fmt.Fprintf(buffer, "\t%s -> %s%s;\n", nodeIDs[i][bindingKey], dependencyNodeID, dotEdgeAttributes(dependency))
+			}
+		}
+	}
+	buffer.WriteString("}\n")
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+// dotDependencyNodeID finds the node of a dependency, looking at the last cluster first
+func dotDependencyNodeID(nodeIDs []map[bindingKey]string, bindingKey bindingKey) (string, bool) {
+	for i := len(nodeIDs) - 1; i >= 0; i-- {
+		if nodeID, ok := nodeIDs[i][bindingKey]; ok {
+			return nodeID, true
+		}
+	}
+	return "", false
+}
+
+func describedBindingKeyLabels(bindingKey bindingKey, kind Kind) []string {
+	labels := []string{elementReflectType(bindingKey.reflectType()).String()}
+	if tag := bindingKeyTag(bindingKey); tag != "" {
+		labels = append(labels, fmt.Sprintf("tag: %s", tag))
+	}
+	return append(labels, fmt.Sprintf("(%s)", kind))
+}
+
+func dotEdgeAttributes(dependency dependency) string {
+	switch {
+	case dependency.lazy:
+		return " [style=dashed]"
+	case dependency.optional:
+		return " [style=dotted]"
+	default:
+		return ""
+	}
+}
+
+// dotLabel returns a quoted DOT string with one line per given line
+func dotLabel(lines ...string) string {
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = dotReplacer.Replace(line)
+	}
+	return fmt.Sprintf(`"%s"`, strings.Join(escaped, `\n`))
+}
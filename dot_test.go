@@ -0,0 +1,96 @@
+package inject
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDOT(t *testing.T) {
+	module := NewModule()
+	module.BindTagged("tagOne", (*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.BindInterface((*BarInterface)(nil)).To(&BarPtrStruct{})
+	module.Bind(&BarPtrStruct{}).ToSingleton(&BarPtrStruct{1})
+	module.Bind((*SecondInterface)(nil)).ToTaggedConstructor(func(str struct {
+		S SimpleInterface `inject:"tagOne"`
+		B BarInterface
+	}) (SecondInterface, error) {
+		return &SecondPtrStruct{str.S, str.B}, nil
+	})
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteDOT(buffer, injector))
+	require.Equal(t, `digraph inject {
+	subgraph cluster_0 {
+		label="injector";
+		n1 [shape=box, label="inject.BarInterface\n(intermediate)"];
+		n2 [shape=box, label="*inject.BarPtrStruct\n(singleton)"];
+		n3 [shape=box, label="inject.Injector\n(singleton)"];
+		n4 [shape=box, label="inject.SecondInterface\n(tagged constructor)"];
+		n5 [shape=box, label="inject.SimpleInterface\ntag: tagOne\n(singleton)"];
+	}
+	n1 -> n2;
+	n4 -> n5;
+	n4 -> n1;
+}
+`, buffer.String())
+}
+
+func TestWriteDOTChildInjector(t *testing.T) {
+	parentModule := NewModule()
+	parentModule.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	parentInjector, err := NewInjector(parentModule)
+	require.NoError(t, err)
+	childModule := NewModule()
+	childModule.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	childModule.Bind((*SecondInterface)(nil)).ToConstructor(func(s Provider[SimpleInterface], b Optional[BarInterface]) (SecondInterface, error) {
+		return nil, nil
+	})
+	childInjector, err := parentInjector.NewChildInjector(childModule)
+	require.NoError(t, err)
+
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteDOT(buffer, childInjector))
+	dot := buffer.String()
+	require.Contains(t, dot, "subgraph cluster_0 {\n\t\tlabel=\"injector\";\n\t\tn1 [shape=box, label=\"inject.Injector\\n(singleton)\"];\n\t\tn2 [shape=box, label=\"inject.SimpleInterface\\n(singleton)\"];\n\t}")
+	require.Contains(t, dot, "subgraph cluster_1 {\n\t\tlabel=\"child injector 1\";")
+	require.Contains(t, dot, "n5 [shape=box, label=\"inject.SecondInterface\\n(constructor)\"];")
+	// the provider points to the binding of the parent injector
+	require.Contains(t, dot, "n5 -> n2 [style=dashed];")
+	require.Contains(t, dot, "n5 -> n3 [style=dotted];")
+}
+
+func TestWriteModuleDOT(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).ToConstructor(createSecondInterface)
+	module.BindTagged("tag\"One", (*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteModuleDOT(buffer, module))
+	lines := strings.Split(buffer.String(), "\n")
+	require.Equal(t, []string{
+		`digraph inject {`,
+		`	n1 [shape=box, label="inject.SecondInterface\n(constructor)"];`,
+		`	n2 [shape=box, label="inject.SimpleInterface\ntag: tag\"One\n(singleton)"];`,
+		`	n3 [shape=box, style=dashed, label="inject.SimpleInterface\n(unbound)"];`,
+		`	n1 -> n3;`,
+		`	n4 [shape=box, style=dashed, label="inject.BarInterface\n(unbound)"];`,
+		`	n1 -> n4;`,
+		`}`,
+		``,
+	}, lines)
+}
+
+func TestWriteModuleDOTPrivateModule(t *testing.T) {
+	privateModule := NewPrivateModule()
+	privateModule.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	privateModule.Expose((*SimpleInterface)(nil))
+
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteModuleDOT(buffer, privateModule))
+	require.Equal(t, "digraph inject {\n\tn1 [shape=box, label=\"inject.SimpleInterface\\n(singleton)\"];\n}\n", buffer.String())
+}
@@ -29,17 +29,15 @@ type Api interface {
 }
 
 type api struct {
-	awsProvider          cloud.Provider
-	digitalOceanProvider cloud.Provider
-	moreThings           more.MoreThings
+	providers  map[string]cloud.Provider
+	moreThings more.MoreThings
 }
 
 func createApi(s struct {
-	AwsProvider          cloud.Provider `inject:"aws"`
-	DigitalOceanProvider cloud.Provider `inject:"digital_ocean"`
-	MoreThings           more.MoreThings
+	Providers  map[string]cloud.Provider
+	MoreThings more.MoreThings
 }) (Api, error) {
-	return &api{s.AwsProvider, s.DigitalOceanProvider, s.MoreThings}, nil
+	return &api{s.Providers, s.MoreThings}, nil
 }
 
 func (a *api) Do(request Request) (*Response, error) {
@@ -63,12 +61,9 @@ func (a *api) Do(request Request) (*Response, error) {
 }
 
 func (a *api) getProvider(provider string) (cloud.Provider, error) {
-	switch provider {
-	case "aws":
-		return a.awsProvider, nil
-	case "digital_ocean":
-		return a.digitalOceanProvider, nil
-	default:
+	p, ok := a.providers[provider]
+	if !ok {
 		return nil, fmt.Errorf("api: Unknown provider %v", provider)
 	}
+	return p, nil
 }
@@ -7,8 +7,9 @@ import (
 
 func NewModule() inject.Module {
 	module := inject.NewModule()
-	module.BindTagged("aws", (*Provider)(nil)).ToSingletonConstructor(createAwsProvider)
-	module.BindTagged("digital_ocean", (*Provider)(nil)).ToSingletonConstructor(createDigitalOceanProvider)
+	providers := module.BindMap((*Provider)(nil))
+	providers.AddSingletonConstructor("aws", createAwsProvider)
+	providers.AddSingletonConstructor("digital_ocean", createDigitalOceanProvider)
 	return module
 }
 
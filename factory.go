@@ -0,0 +1,197 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+const (
+	// the parameter is injected from a binding
+	injectedParameter = -1
+	// the parameter is a leading context.Context that gets the context of the caller
+	contextParameter = -2
+)
+
+// assistedCall is a function whose parameters are either supplied by the caller
+// as arguments, or injected
+type assistedCall struct {
+	funcReflectType reflect.Type
+	// for every parameter of the function, the index of the argument supplied by
+	// the caller, or injectedParameter or contextParameter
+	argumentIndexes []int
+	// the dependencies of the injected parameters, in order
+	dependencies []dependency
+}
+
+// newAssistedCall matches every argument to the first unmatched parameter of
+// the function it is assignable to, and injects all other parameters
+func newAssistedCall(funcReflectType reflect.Type, argumentReflectTypes []reflect.Type) (*assistedCall, error) {
+	if !isFunc(funcReflectType) {
+		return nil, ErrNotFunction.withTag("funcReflectType", funcReflectType)
+	}
+	numIn := funcReflectType.NumIn()
+	assistedCall := &assistedCall{funcReflectType, make([]int, numIn), nil}
+	used := make([]bool, len(argumentReflectTypes))
+	for i := 0; i < numIn; i++ {
+		parameterReflectType := funcReflectType.In(i)
+		assistedCall.argumentIndexes[i] = injectedParameter
+		for ii, argumentReflectType := range argumentReflectTypes {
+			if !used[ii] && argumentReflectType.AssignableTo(parameterReflectType) {
+				assistedCall.argumentIndexes[i] = ii
+				used[ii] = true
+				break
+			}
+		}
+		if assistedCall.argumentIndexes[i] != injectedParameter {
+			continue
+		}
+		if i == 0 && parameterReflectType == contextReflectType {
+			assistedCall.argumentIndexes[i] = contextParameter
+			continue
+		}
+		dependency := newDependency(parameterReflectType, "")
+		if err := verifyDependencyCanBeInjected(dependency); err != nil {
+			return nil, err
+		}
+		assistedCall.dependencies = append(assistedCall.dependencies, dependency)
+	}
+	for ii, argumentReflectType := range argumentReflectTypes {
+		if !used[ii] {
+			return nil, ErrArgumentNotUsed.withTag("funcReflectType", funcReflectType).withTag("argumentReflectType", argumentReflectType)
+		}
+	}
+	return assistedCall, nil
+}
+
+// reflectValues returns the parameters to call the function with
+func (a *assistedCall) reflectValues(ctx context.Context, r *resolution, injector *injector, arguments []reflect.Value) ([]reflect.Value, error) {
+	injectedReflectValues, err := injector.getReflectValues(ctx, r, a.dependencies)
+	if err != nil {
+		return nil, err
+	}
+	reflectValues := make([]reflect.Value, len(a.argumentIndexes))
+	for i, argumentIndex := range a.argumentIndexes {
+		switch argumentIndex {
+		case injectedParameter:
+			reflectValues[i] = injectedReflectValues[0]
+			injectedReflectValues = injectedReflectValues[1:]
+		case contextParameter:
+			reflectValues[i] = reflect.ValueOf(&ctx).Elem()
+		default:
+			reflectValues[i] = arguments[argumentIndex]
+		}
+	}
+	return reflectValues, nil
+}
+
+type factoryBinding struct {
+	constructor interface{}
+	cache       *factoryBindingCache
+	injector    *injector
+}
+
+type factoryBindingCache struct {
+	factoryReflectType reflect.Type
+	assistedCall       *assistedCall
+}
+
+func newFactoryBinding(factoryReflectType reflect.Type) func(interface{}) binding {
+	return func(constructor interface{}) binding {
+		// verified by verifyFactoryReflectType
+		assistedCall, _ := newFactoryAssistedCall(factoryReflectType, reflect.TypeOf(constructor))
+		return &factoryBinding{constructor, &factoryBindingCache{factoryReflectType, assistedCall}, nil}
+	}
+}
+
+func newFactoryAssistedCall(factoryReflectType reflect.Type, constructorReflectType reflect.Type) (*assistedCall, error) {
+	numIn := factoryReflectType.NumIn()
+	argumentReflectTypes := make([]reflect.Type, numIn)
+	for i := 0; i < numIn; i++ {
+		argumentReflectTypes[i] = factoryReflectType.In(i)
+	}
+	assistedCall, err := newAssistedCall(constructorReflectType, argumentReflectTypes)
+	if err != nil {
+		return nil, err
+	}
+	// the injected values are only resolved when the factory is called
+	for i := range assistedCall.dependencies {
+		assistedCall.dependencies[i].lazy = true
+	}
+	return assistedCall, nil
+}
+
+func (f *factoryBinding) String() string {
+	return fmt.Sprintf("%v", f.constructor)
+}
+
+func (f *factoryBinding) validate() error {
+	return f.injector.validateDependencies(f.cache.assistedCall.dependencies)
+}
+
+func (f *factoryBinding) get(ctx context.Context, r *resolution) (interface{}, error) {
+	factoryReflectType := f.cache.factoryReflectType
+	valueReflectType := factoryReflectType.Out(0)
+	return reflect.MakeFunc(factoryReflectType, func(arguments []reflect.Value) []reflect.Value {
+		// as for Providers, the resolution is continued while the value the
+		// factory is injected into is constructed
+		callResolution := r
+		if r == nil || r.parent.active() == nil {
+			callResolution = nil
+		}
+		value, err := f.call(ctx, callResolution, arguments)
+		if err != nil {
+			return []reflect.Value{reflect.Zero(valueReflectType), reflect.ValueOf(&err).Elem()}
+		}
+		return []reflect.Value{reflectValueOf(value, valueReflectType), reflect.Zero(errorReflectType)}
+	}).Interface(), nil
+}
+
+func (f *factoryBinding) call(ctx context.Context, r *resolution, arguments []reflect.Value) (interface{}, error) {
+	reflectValues, err := f.cache.assistedCall.reflectValues(ctx, r, f.injector, arguments)
+	if err != nil {
+		return nil, err
+	}
+	return constructAndInitialize(ctx, r, f.constructor, reflectValues, nil)
+}
+
+func (f *factoryBinding) constructed() bool {
+	return false
+}
+
+func (f *factoryBinding) kind() Kind {
+	return FactoryKind
+}
+
+func (f *factoryBinding) dependencies() []dependency {
+	return f.cache.assistedCall.dependencies
+}
+
+func (f *factoryBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
+	return &factoryBinding{f.constructor, f.cache, injector}, nil
+}
+
+// verifyFactoryReflectType verifies that the factory returns a value and an error,
+// and that the constructor takes every parameter of the factory and returns the value
+func verifyFactoryReflectType(factoryReflectType reflect.Type, constructorReflectType reflect.Type) error {
+	if !isFactory(factoryReflectType) {
+		return ErrFactoryInvalid.withTag("factoryReflectType", factoryReflectType)
+	}
+	if _, err := newFactoryAssistedCall(factoryReflectType, constructorReflectType); err != nil {
+		return err
+	}
+	valueReflectType := factoryReflectType.Out(0)
+	if isInterface(valueReflectType) {
+		valueReflectType = reflect.PtrTo(valueReflectType)
+	}
+	return verifyConstructorReturnValues(valueReflectType, constructorReflectType)
+}
+
+// isFactory returns true for functions that take at least one parameter and
+// return a value and an error
+func isFactory(reflectType reflect.Type) bool {
+	return isFunc(reflectType) &&
+		reflectType.NumIn() > 0 &&
+		reflectType.NumOut() == 2 &&
+		reflectType.Out(1) == errorReflectType
+}
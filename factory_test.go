@@ -0,0 +1,155 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errXYZ = errors.New("XYZ")
+
+type Command struct {
+	Path string
+}
+
+type SecondInterfaceFactory func(s SimpleInterface) (SecondInterface, error)
+
+func createSecondInterfaceForCommand(command Command, b BarInterface) (SecondInterface, error) {
+	return &SecondPtrStruct{&SimplePtrStruct{command.Path}, b}, nil
+}
+
+func TestCallWith(t *testing.T) {
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			values, err := injector.CallWith(createSecondInterfaceForCommand, Command{"ls"})
+			require.NoError(t, err)
+			require.Equal(t, "ls", values[0].(SecondInterface).Foo().Foo())
+			require.Equal(t, 1, values[0].(SecondInterface).Bar().Bar())
+
+			// arguments are assignable to interface parameters
+			values, err = injector.CallWith(createSecondInterface, &SimplePtrStruct{"hello"})
+			require.NoError(t, err)
+			require.Equal(t, "hello", values[0].(SecondInterface).Foo().Foo())
+
+			_, err = injector.CallWith(createSecondInterfaceForCommand, Command{"ls"}, 1)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), injectErrorTypeArgumentNotUsed)
+
+			_, err = injector.CallWith(createSecondInterfaceForCommand)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), injectErrorTypeNoBinding)
+		})
+	}
+}
+
+func TestCallWithContext(t *testing.T) {
+	injector, err := NewInjector()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	values, err := injector.CallWith(func(ctx context.Context, command Command) string {
+		return ctx.Value(contextKey{}).(string) + command.Path
+	}, Command{"ls"}, ctx)
+	require.NoError(t, err)
+	require.Equal(t, "valuels", values[0])
+}
+
+func TestToFactory(t *testing.T) {
+	count := 0
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToConstructor(func() (BarInterface, error) {
+		count++
+		return &BarPtrStruct{count}, nil
+	})
+	module.Bind((func(Command) (SecondInterface, error))(nil)).ToFactory(createSecondInterfaceForCommand)
+	module.Bind(SecondInterfaceFactory(nil)).ToFactory(createSecondInterface)
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			count = 0
+			values, err := injector.Call(func(factory func(Command) (SecondInterface, error), secondInterfaceFactory SecondInterfaceFactory) error {
+				secondInterface, err := factory(Command{"ls"})
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "ls", secondInterface.Foo().Foo())
+				secondInterface, err = secondInterfaceFactory(&SimplePtrStruct{"hello"})
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "hello", secondInterface.Foo().Foo())
+				return nil
+			})
+			require.NoError(t, err)
+			require.Nil(t, values[0])
+			// injected parameters are resolved every time a factory is called
+			require.Equal(t, 2, count)
+		})
+	}
+}
+
+type CommandSecondInterfaceFactory func(Command) (SecondInterface, error)
+
+type OtherCommandSecondInterfaceFactory func(Command) (SecondInterface, error)
+
+func TestToFactoryMultipleBindingKeys(t *testing.T) {
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	module.Bind(CommandSecondInterfaceFactory(nil), OtherCommandSecondInterfaceFactory(nil)).ToFactory(createSecondInterfaceForCommand)
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			values, err := injector.Call(func(commandFactory CommandSecondInterfaceFactory, otherCommandFactory OtherCommandSecondInterfaceFactory) error {
+				secondInterface, err := commandFactory(Command{"ls"})
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "ls", secondInterface.Foo().Foo())
+				secondInterface, err = otherCommandFactory(Command{"cd"})
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "cd", secondInterface.Foo().Foo())
+				return nil
+			})
+			require.NoError(t, err)
+			require.Nil(t, values[0])
+		})
+	}
+}
+
+func TestToFactoryReturnsConstructorError(t *testing.T) {
+	module := NewModule()
+	module.Bind((func(Command) (SecondInterface, error))(nil)).ToFactory(func(command Command) (SecondInterface, error) {
+		return nil, errXYZ
+	})
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	object, err := injector.Get((func(Command) (SecondInterface, error))(nil))
+	require.NoError(t, err)
+	_, err = object.(func(Command) (SecondInterface, error))(Command{"ls"})
+	require.True(t, errors.Is(err, errXYZ))
+}
+
+func TestToFactoryUnboundFailsValidation(t *testing.T) {
+	module := NewModule()
+	module.Bind((func(Command) (SecondInterface, error))(nil)).ToFactory(createSecondInterfaceForCommand)
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeNoBinding)
+}
+
+func TestToFactoryInvalid(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).ToFactory(createSecondInterfaceForCommand)
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeFactoryInvalid)
+
+	module = NewModule()
+	module.Bind((func(string) (SecondInterface, error))(nil)).ToFactory(createSecondInterfaceForCommand)
+	_, err = NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeArgumentNotUsed)
+}
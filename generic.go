@@ -0,0 +1,78 @@
+package inject
+
+import (
+	"context"
+	"reflect"
+)
+
+// Get gets the value bound to T from the Injector.
+//
+// This is the type-safe equivalent of injector.Get((*T)(nil)) for an interface T,
+// or injector.Get(T{}) otherwise, and shares the same bindings.
+func Get[T any](injector Injector) (T, error) {
+	return GetContext[T](context.Background(), injector)
+}
+
+// GetContext is like Get, but passes ctx to constructors that take a
+// context.Context as their first parameter.
+func GetContext[T any](ctx context.Context, injector Injector) (T, error) {
+	obj, err := injector.GetContext(ctx, bindingReflectTypeFor[T]())
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return valueFor[T](obj)
+}
+
+// GetTagged gets the value bound to T with the given tag from the Injector.
+//
+// This is the type-safe equivalent of injector.GetTagged(tag, (*T)(nil)) for an interface T,
+// or injector.GetTagged(tag, T{}) otherwise, and shares the same bindings.
+func GetTagged[T any](injector Injector, tag string) (T, error) {
+	return GetTaggedContext[T](context.Background(), injector, tag)
+}
+
+// GetTaggedContext is like GetTagged, but passes ctx to constructors that take
+// a context.Context as their first parameter.
+func GetTaggedContext[T any](ctx context.Context, injector Injector, tag string) (T, error) {
+	obj, err := injector.GetTaggedContext(ctx, tag, bindingReflectTypeFor[T]())
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return valueFor[T](obj)
+}
+
+// Bind binds T in the Module.
+//
+// This is the equivalent of module.Bind((*T)(nil)) for an interface T,
+// or module.Bind(T{}) otherwise.
+func Bind[T any](module Module) Builder {
+	return module.Bind(bindingReflectTypeFor[T]())
+}
+
+// BindTagged binds T with the given tag in the Module.
+//
+// This is the equivalent of module.BindTagged(tag, (*T)(nil)) for an interface T,
+// or module.BindTagged(tag, T{}) otherwise.
+func BindTagged[T any](module Module, tag string) Builder {
+	return module.BindTagged(tag, bindingReflectTypeFor[T]())
+}
+
+// bindingReflectTypeFor returns the reflect.Type used in binding keys for T,
+// which is a pointer to T if T is an interface.
+func bindingReflectTypeFor[T any]() reflect.Type {
+	return bindingKeyReflectTypeOf(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func valueFor[T any](obj interface{}) (T, error) {
+	var zero T
+	if obj == nil {
+		return zero, nil
+	}
+	value, ok := obj.(T)
+	if !ok {
+		return zero, ErrNotAssignable.withTag("bindingKeyReflectType", reflect.TypeOf((*T)(nil)).Elem()).withTag("bindingReflectType", reflect.TypeOf(obj))
+	}
+	return value, nil
+}
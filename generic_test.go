@@ -0,0 +1,73 @@
+package inject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenericGet(t *testing.T) {
+	module := NewModule()
+	Bind[SimpleInterface](module).ToSingleton(&SimplePtrStruct{"hello"})
+	Bind[BarInterface](module).ToSingleton(&BarPtrStruct{1})
+	Bind[SecondInterface](module).ToConstructor(createSecondInterface)
+	Bind[*SimplePtrStruct](module).ToSingleton(&SimplePtrStruct{"ptr"})
+	Bind[SimpleStruct](module).ToSingleton(SimpleStruct{"struct"})
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			secondInterface, err := Get[SecondInterface](injector)
+			require.NoError(t, err)
+			require.Equal(t, "hello", secondInterface.Foo().Foo())
+			require.Equal(t, 1, secondInterface.Bar().Bar())
+
+			simplePtrStruct, err := Get[*SimplePtrStruct](injector)
+			require.NoError(t, err)
+			require.Equal(t, "ptr", simplePtrStruct.Foo())
+
+			simpleStruct, err := Get[SimpleStruct](injector)
+			require.NoError(t, err)
+			require.Equal(t, "struct", simpleStruct.Foo())
+
+			// shares the bindings of the untyped API
+			object, err := injector.Get((*SecondInterface)(nil))
+			require.NoError(t, err)
+			require.Equal(t, "hello", object.(SecondInterface).Foo().Foo())
+
+			_, err = Get[UnboundInterface](injector)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), injectErrorTypeNoBinding)
+		})
+	}
+}
+
+func TestGenericGetTagged(t *testing.T) {
+	module := NewModule()
+	BindTagged[SimpleInterface](module, "tagOne").ToSingleton(SimpleStruct{"hello"})
+	module.BindTaggedInt("intTag").ToSingleton(10)
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			simpleInterface, err := GetTagged[SimpleInterface](injector, "tagOne")
+			require.NoError(t, err)
+			require.Equal(t, "hello", simpleInterface.Foo())
+
+			i, err := GetTagged[int](injector, "intTag")
+			require.NoError(t, err)
+			require.Equal(t, 10, i)
+
+			_, err = Get[SimpleInterface](injector)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), injectErrorTypeNoBinding)
+			_, err = GetTagged[SimpleInterface](injector, "tagTwo")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), injectErrorTypeNoBinding)
+		})
+	}
+}
+
+func TestGenericBindNotAssignable(t *testing.T) {
+	module := NewModule()
+	Bind[SimpleInterface](module).ToSingleton(SimplePtrStruct{"hello"})
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeNotAssignable)
+}
@@ -1,6 +1,6 @@
 module go.pedge.io/inject
 
-go 1.12
+go 1.20
 
 require (
 	github.com/davecgh/go-spew v1.1.0
@@ -31,6 +31,12 @@ An interface can have a binding to another type, or to a singleton or constructo
 	module.BindInterface((*SayHello)(nil)).To(&SayHelloOne{}) // valid, but must provide a binding to *SayHelloOne.
 	module.Bind(&SayHelloOne{}).ToSingleton(&SayHelloOne{"Salutations"}) // there we go
 
+The binding of *SayHelloOne can be declared in any Module of the Injector or of a parent injector.
+An interface can also be linked to another interface, which is linked in turn, or to a tagged binding:
+
+	module.BindInterface((*SayHello)(nil)).ToTagged("english", &SayHelloOne{})
+	module.BindTagged("english", &SayHelloOne{}).ToSingleton(&SayHelloOne{"Hello"})
+
 An interface can also be bound to a singleton or constructor.
 
 	module.Bind((*SayHello)(nil)).ToSingleton(&SayHelloOne{"Salutations"})
@@ -39,6 +45,22 @@ A struct, struct pointer, or primitive must have a direct binding to a singleton
 
 All errors from binding will be returned as one error when calling inject.NewInjector(...).
 
+Libraries can ship Module types of their own by implementing Configurer, in the style of Guice's
+AbstractModule.configure. NewConfiguredModule creates a Module with the bindings declared by a
+Configurer, and a Module implementation that also implements Configurer can be installed, overridden
+and passed to NewInjector like any other Module:
+
+	type awsModule struct {
+		region string
+	}
+
+	func (a awsModule) Configure(binder inject.Binder) {
+		binder.BindTaggedString("aws.region").ToSingleton(a.region)
+		binder.BindTagged("aws", (*cloud.Provider)(nil)).ToTaggedSingletonConstructor(newAWSProvider)
+	}
+
+	module.Install(inject.NewConfiguredModule(awsModule{"us-east-1"}))
+
 
 Injector
 
@@ -170,6 +192,17 @@ In addition, an eager singleton can be combined with an additional arbitrary fun
 		return module
 	}
 
+Tagged singletons are eager in the same way, and a function taking an anonymous struct with
+tagged fields, as for CallTagged, gets tagged values. A singleton bound to several binding keys is
+constructed once and shared by all of them.
+
+	module.BindTagged("aws", (*Provider)(nil)).ToSingletonConstructor(newAWSProvider).EagerlyAndCall(
+		func(str struct {
+			Provider Provider `inject:"aws"`
+		}) {
+			...
+		})
+
 
 Calling Arbitrary Functions
 
@@ -336,6 +369,97 @@ A constructor can mix tagged values with untagged values in the input struct.
 The CallTagged function works similarly to Call, except can take parameters like a tagged constructor.
 
 
+Optional Dependencies
+
+A struct field with the "optional" tag option is left as its zero value if there is no binding for it,
+instead of resulting in an error. The tag name may be empty for untagged bindings. The "optional" tag
+option is not supported for providers.
+
+	type Reporter struct {
+		Metrics MetricsSink `inject:",optional"`
+		English SayHello `inject:"english,optional"`
+	}
+
+A constructor or called function can take an Optional parameter, or struct field, instead. Present is
+false if there is no binding.
+
+	func newServer(metrics inject.Optional[MetricsSink]) (*Server, error) {
+		if metrics.Present {
+			...
+		}
+	}
+
+Optional dependencies are not checked by NewInjector, but required ones still are.
+
+
+Providers
+
+A constructor, called function or populated struct can take a Provider, or equivalently a
+func() (T, error), instead of T. Each call of the provider resolves T, so T is only constructed
+when needed, and a new value is constructed every call unless T is a singleton.
+
+	func newReporter(newConnection inject.Provider[*Connection]) (*Reporter, error) {
+		return &Reporter{newConnection}, nil
+	}
+
+	func (r *Reporter) Report() error {
+		connection, err := r.newConnection()
+		...
+	}
+
+NewInjector still checks that T is bound. Since T is resolved lazily, a provider can be used to
+break a dependency cycle, as long as it is only called after the value it is injected into was
+constructed. Calling it from the constructor in a cycle returns an ErrDependencyCycle.
+
+
+Factories
+
+Some values need arguments that are only known at runtime in addition to injected ones. CallWith
+calls a function with the given arguments for the parameters they are assignable to, and injects
+the rest.
+
+	func newInstance(command Command, provider Provider) (Instance, error) { ... }
+
+	values, err := injector.CallWith(newInstance, Command{Path: "ls"})
+
+A function type taking the runtime arguments and returning a value and an error can be bound to such
+a constructor with ToFactory, and injected like any other binding. The other parameters of the
+constructor are injected every time the factory is called, and NewInjector checks that they are bound.
+
+	module.Bind((func(Command) (Instance, error))(nil)).ToFactory(newInstance)
+
+	func newRunner(newInstance func(Command) (Instance, error)) (*Runner, error) { ... }
+
+
+Provider Methods
+
+ModuleFromProviders binds every exported method of a struct whose name starts with "Provide" to its first
+return type, like Guice's @Provides methods. The fields of the struct are available to the methods as
+configuration. Methods are bound as constructors, or as tagged constructors if they take an anonymous
+struct, and the struct can implement ProviderBindingOptions to give methods a tag or bind them as
+(eager) singletons:
+
+	type Providers struct {
+		DSN string
+	}
+
+	func (p *Providers) ProvideDB() (*sql.DB, error) {
+		return sql.Open("postgres", p.DSN)
+	}
+
+	func (p *Providers) ProvideUserStore(db *sql.DB) UserStore {
+		return &sqlUserStore{db}
+	}
+
+	func (p *Providers) BindingOptions() map[string]inject.ProviderOptions {
+		return map[string]inject.ProviderOptions{
+			"ProvideDB": {Singleton: true, Eager: true},
+		}
+	}
+
+	module := inject.ModuleFromProviders(&Providers{DSN: dsn})
+
+
 Child Injectors
 
 A child injector is built from an existing injector (it's parent). It inherits all bindings and singletons of its parent
@@ -350,10 +474,247 @@ See this discussion on hierarchical injectors for further information and possib
 https://publicobject.com/2008/06/whats-hierarchical-injector.html
 
 
+Private Modules
+
+The bindings of a PrivateModule are only visible to the other bindings of the PrivateModule, except for
+the bindings it exposes. This keeps helper types of a module from colliding with, or being used by, other
+modules. Depending on a private binding from outside of the PrivateModule results in an ErrPrivateBinding.
+
+	func NewModule() inject.Module {
+		module := inject.NewPrivateModule()
+		module.Bind(&client{}).ToSingletonConstructor(newClient)
+		module.BindTagged("aws", (*cloud.Provider)(nil)).ToConstructor(newAWSProvider)
+		module.ExposeTagged("aws", (*cloud.Provider)(nil))
+		return module
+	}
+
+
+Initialization
+
+Values constructed by the injector can implement Initializer to be initialized right after their
+constructor returns. Init is called once for a singleton, and every time a value is constructed for
+other bindings, with the context of the caller. An error returned by Init, or a panic, is returned
+as an ErrInitFailed with the binding key. For types that cannot implement Initializer, a function can be
+bound with OnConstruct before the constructor:
+
+	module.Bind(&http.Server{}).OnConstruct(func(server *http.Server) error {
+		server.ErrorLog = logger
+		return nil
+	}).ToSingletonConstructor(newServer)
+
+OnConstruct functions are called after Init, in the order they were added.
+
+
+Closing
+
+Singletons that hold resources can implement Stopper or io.Closer. Closing the injector stops
+every singleton it actually constructed, in the reverse order of construction, so that a singleton
+is always stopped before the singletons it depends on.
+
+	type Database struct { ... }
+
+	func (d *Database) Close() error { ... }
+
+	func run(module inject.Module) (retErr error) {
+		injector, err := inject.NewInjector(module)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := injector.Close(context.Background()); err != nil && retErr == nil {
+				retErr = err
+			}
+		}()
+		...
+	}
+
+Closing a child injector only stops the singletons constructed by the child injector.
+
+
+Contexts
+
+A constructor or called function whose first parameter is a context.Context does not need a binding
+for it. Instead, it receives the context passed to GetContext, GetTaggedContext, CallContext or
+CallTaggedContext, or context.Background() when using the methods without a context.
+
+	func newDatabase(ctx context.Context, config *Config) (*Database, error) {
+		return dial(ctx, config.Address)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	database, err := injector.GetContext(ctx, &Database{})
+
+Eager singletons receive the context passed to NewInjectorContext, including its cancellation and
+deadline. Other singletons receive the values of the context of whoever first needs them, but not
+its cancellation and deadline, so that a canceled request cannot make a singleton fail for good.
+
+
+Scopes
+
+A Scope controls how the values created by a constructor are shared. Bindings without a scope
+create a new value every time (or exactly once for singletons). The built-in RequestScope
+creates at most one value per request context.
+
+	module.Bind((*RequestLogger)(nil)).In(inject.RequestScope).ToConstructor(newRequestLogger)
+
+	func handle(w http.ResponseWriter, r *http.Request) {
+		ctx := inject.NewRequestContext(r.Context())
+		// every resolution with ctx shares the same RequestLogger
+		handler, err := injector.GetContext(ctx, (*Handler)(nil))
+		...
+	}
+
+Custom scopes can be implemented with the Scope interface.
+
+
+Multibindings
+
+A set binding collects elements from any number of modules, and is injected as a slice.
+This allows plugins spread across modules to each contribute an implementation of a shared
+interface.
+
+	type Handler interface { ... }
+
+	m1 := inject.NewModule()
+	m1.BindSet((*Handler)(nil)).AddSingleton(&fooHandler{})
+	m2 := inject.NewModule()
+	m2.BindSet((*Handler)(nil)).AddConstructor(newBarHandler)
+
+	func newServer(handlers []Handler) (*Server, error) { ... } // gets both handlers
+
+Elements are injected in a deterministic order: in the order they were added, with modules
+passed first to NewInjector coming first, and the elements of a parent injector coming before
+those of a child injector. Set bindings are combined by Install, and the elements of a module
+that is installed more than once are only added once. Override replaces all elements of a set with
+those of the override modules.
+
+
+A map binding is similar, but each entry is added with a string key, and is injected as a map.
+Adding the same key twice results in an error.
+
+	awsModule := inject.NewModule()
+	awsModule.BindMap((*Provider)(nil)).AddSingletonConstructor("aws", newAwsProvider)
+	gceModule := inject.NewModule()
+	gceModule.BindMap((*Provider)(nil)).AddSingletonConstructor("gce", newGceProvider)
+
+	func newApi(providers map[string]Provider) (*Api, error) { ... } // gets both providers
+
+
+Generics
+
+Get, GetTagged, Bind and BindTagged are type-safe alternatives to the methods on Injector and Module.
+They share the same bindings, so values bound with the untyped API can be retrieved with the typed
+API and vice versa.
+
+	func doStuff() error {
+		module := inject.NewModule()
+		inject.Bind[SayHello](module).ToSingleton(&SayHelloOne{"Salutations"})
+		inject.BindTagged[SayHello](module, "german").ToSingleton(&SayHelloOne{"Guten Tag"})
+		injector, err := inject.NewInjector(module)
+		if err != nil {
+			return err
+		}
+		sayHello, err := inject.Get[SayHello](injector) // no type assertion needed
+		if err != nil {
+			return err
+		}
+		fmt.Println(sayHello.Hello()) // will print "Salutations"
+		sayHelloGerman, err := inject.GetTagged[SayHello](injector, "german")
+		if err != nil {
+			return err
+		}
+		fmt.Println(sayHelloGerman.Hello()) // will print "Guten Tag"
+		return nil
+	}
+
+
+Configuration
+
+The config package builds a Module of tagged constant bindings from environment variables, flags, JSON
+files or .properties files. Every key is bound with its name as the tag:
+
+	module := config.NewModule(config.Env("APP_"), config.String("db.host"), config.Uint16("db.port"))
+
+Values that cannot be parsed are returned as errors by NewInjector. Modules can report errors of their
+own, such as errors reading configuration, with Module.AddError.
+
+Tagged constants are injected by their exact type. Besides the primitive types, time.Duration, time.Time,
+[]string, *url.URL and net.IP have their own BindTagged methods, and named types whose underlying type is
+a bool, number or string are bound with Module.BindTaggedConstant:
+
+	type Port int
+
+	module.BindTaggedConstant("port", Port(0)).ToSingleton(Port(8080))
+	// or from the configuration
+	configModule := config.NewModule(config.Env("APP_"), config.Constant("port", Port(0)), config.Duration("timeout"))
+
+
+Options
+
+NewInjectorWithOptions creates an Injector with Options, which control when singletons are constructed
+(Stage), whether duplicate bindings replace each other (LenientOverrides), logging (Logger), observing
+every value gotten (Observer), the key of the struct tags of tagged constructors (TagKey), and whether
+bindings that no eager singleton depends on are validated (SkipUnreachableValidation). The zero Options
+creates the same Injector as NewInjector.
+
+	injector, err := inject.NewInjectorWithOptions(inject.Options{Stage: inject.Production}, modules...)
+
+In the Production stage, all singletons are constructed when the Injector is created, in dependency
+order, so that misconfiguration fails fast, and the Logger gets the number of errors of each type if
+creating the Injector fails. The Development stage, the default, only constructs eager singletons when
+the Injector is created and logs every error. The Tool stage constructs no singleton and calls no
+EagerlyAndCall function when the Injector is created.
+
+
+Errors
+
+All errors are an *Error, or wrap one, created from one of the Err variables such as ErrNoBinding.
+Use errors.Is to check for a specific error, and errors.As to get the key of the binding the error
+is about. Errors returned by constructors are wrapped in an ErrConstructorFailed, and can be
+retrieved with errors.Is, errors.As or errors.Unwrap.
+
+	_, err := injector.Get((*SayHello)(nil))
+	if errors.Is(err, inject.ErrNoBinding) {
+		...
+	}
+
+NewInjector and NewChildInjector report every invalid binding, duplicate binding and missing
+binding at once. The errors are joined with errors.Join and sorted by binding key, so errors.Is
+and errors.As find any of them. Errors about missing bindings have a requiredBy tag with the key
+of the binding that needs the missing binding. A binding that is invalid is only reported once,
+not as missing for the bindings that need it.
+
+Errors getting values are a *ResolutionError with the path of bindings that were being
+resolved, from the requested binding to the binding that failed:
+
+	resolving {type:*api.Api} -> {type:*cloud.Provider tag:aws} -> {type:*stuff.StuffService}: ...
+
+Constructors that panic do not crash the process, the panic is returned as an
+ErrConstructorPanicked instead.
+
+
 Diagnostics
 
-Both Module and Injector implement fmt.Stringer for inspection, however this may be added to in the future
-to allow semantic inspection of bindings.
+Both Module and Injector implement fmt.Stringer for inspection.
+
+Bindings returns a BindingInfo for every binding of a Module or Injector, with its key, kind, the
+keys of its dependencies, its scope, the module it was declared in, and whether its value was already
+constructed. This is the basis for building tools around the bindings.
+
+	for _, binding := range injector.Bindings() {
+		fmt.Printf("%v %s constructed:%v\n", binding.Key, binding.Kind, binding.Constructed)
+	}
+
+WriteDOT writes the dependency graph of an Injector in the Graphviz DOT format, with one node per binding
+key and edges from bindings to their dependencies. The bindings of parent injectors are drawn in separate
+clusters. WriteModuleDOT does the same for a single Module.
+
+	if err := inject.WriteDOT(os.Stdout, injector); err != nil {
+		return err
+	}
+
+	$ go run ./cmd/server | dot -Tsvg > bindings.svg
 
 
 Unit Testing
@@ -371,15 +732,16 @@ For testing, production modules may be overridden with test bindings as follows:
 package inject // import "go.pedge.io/inject"
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"time"
 )
 
-// Module sets up your dependencies.
-//
-// Note that none of the calls to Module are thread-safe, it is your responsibility
-// to make sure multiple goroutines are not calling a single module.
-type Module interface {
-	fmt.Stringer
+// Binder declares bindings, it is implemented by all modules and passed to
+// Configurer.Configure.
+type Binder interface {
 	BindConstructor(fn interface{})
 	BindSingletonConstructor(fn interface{}) SingletonBuilder
 	Bind(from ...interface{}) Builder
@@ -402,31 +764,152 @@ type Module interface {
 	BindTaggedComplex64(tag string) Builder
 	BindTaggedComplex128(tag string) Builder
 	BindTaggedString(tag string) Builder
+	BindTaggedDuration(tag string) Builder
+	BindTaggedTime(tag string) Builder
+	BindTaggedStrings(tag string) Builder
+	BindTaggedURL(tag string) Builder
+	BindTaggedIP(tag string) Builder
+	// BindTaggedConstant binds a tagged constant of the type of from, which is either
+	// the type of one of the other BindTagged methods for constants, or a named type
+	// whose underlying type is a bool, number or string, such as type Port int.
+	// Constants are injected by their exact type, so a Port must be bound with
+	// BindTaggedConstant(tag, Port(0)) and not with BindTaggedInt(tag).
+	BindTaggedConstant(tag string, from interface{}) Builder
+	// BindSet binds a slice of from, to which any Module can add elements
+	// with the returned SetBuilder. The slice is injected as []T, where T is
+	// the interface that from points to, or the type of from otherwise.
+	BindSet(from interface{}) SetBuilder
+	// BindMap binds a map from string keys to values of from, to which any
+	// Module can add entries with the returned MapBuilder. The map is injected
+	// as map[string]T, where T is the interface that from points to, or the
+	// type of from otherwise. Adding the same key twice results in an error.
+	BindMap(from interface{}) MapBuilder
 	Install(others ...Module)
+	// AddError adds an error that is returned together with all other binding
+	// errors when creating an Injector with the Module, such as an error
+	// reading the configuration of bindings.
+	AddError(err error)
+}
+
+// Module sets up your dependencies.
+//
+// Note that none of the calls to Module are thread-safe, it is your responsibility
+// to make sure multiple goroutines are not calling a single module.
+//
+// Modules other than those created with NewModule, NewPrivateModule or
+// NewConfiguredModule must implement Configurer, see Configurer.
+type Module interface {
+	fmt.Stringer
+	Binder
+	// Bindings returns the bindings of the Module, including those of installed
+	// modules, sorted by key.
+	Bindings() []BindingInfo
+}
+
+// Configurer declares the bindings of a Module of your own type, in the style of
+// Guice's AbstractModule.configure. A Module that implements Configurer can be
+// installed, overridden and passed to NewInjector like the modules of this
+// package, with the bindings it declares when Configure is called. Configure is
+// called every time the Module is used, such as a wrapper that installs another
+// Module and logs its bindings:
+//
+//	type loggingModule struct {
+//		inject.Module
+//	}
+//
+//	func (l loggingModule) Configure(binder inject.Binder) {
+//		log.Print(l.Module)
+//		binder.Install(l.Module)
+//	}
+type Configurer interface {
+	Configure(binder Binder)
+}
+
+// NewConfiguredModule creates a new Module with the bindings declared by configurer.
+func NewConfiguredModule(configurer Configurer) Module {
+	module := newModule()
+	configurer.Configure(module)
+	return module
 }
 
 // NewModule creates a new Module.
 func NewModule() Module { return newModule() }
 
+// PrivateModule is a Module whose bindings are only visible to the other bindings
+// of the PrivateModule, including those of installed modules, except for the
+// bindings it exposes. The bindings of a PrivateModule can depend on all bindings
+// visible to the module or injector it is installed to.
+type PrivateModule interface {
+	Module
+	// Expose makes the bindings of from visible to the module or injector the
+	// PrivateModule is installed to.
+	Expose(from ...interface{})
+	// ExposeTagged is like Expose for tagged bindings.
+	ExposeTagged(tag string, from ...interface{})
+}
+
+// NewPrivateModule creates a new PrivateModule.
+func NewPrivateModule() PrivateModule { return newPrivateModule() }
+
 // Builder is the return value from a Bind call from a Module.
 type Builder interface {
+	// In returns a Builder that binds constructors in the given Scope. Only
+	// ToConstructor and ToTaggedConstructor can be used with a Scope.
+	In(scope Scope) Builder
+	// OnConstruct returns a Builder that calls fn with every value constructed
+	// for the binding, after its Init method if it implements Initializer. fn
+	// takes the value, optionally after a context.Context, and returns an
+	// error. This is useful for types that cannot implement Initializer. Only
+	// the constructor bindings can be used with OnConstruct.
+	OnConstruct(fn interface{}) Builder
 	ToSingleton(singleton interface{})
 	ToConstructor(constructor interface{})
 	ToSingletonConstructor(constructor interface{}) SingletonBuilder
 	ToTaggedConstructor(constructor interface{})
 	ToTaggedSingletonConstructor(constructor interface{}) SingletonBuilder
+	// ToFactory binds a factory function type, such as func(Command) (Instance, error),
+	// to a constructor that takes every parameter of the factory. The other parameters
+	// of the constructor are injected every time the factory is called.
+	ToFactory(constructor interface{})
+}
+
+// SetBuilder is the return value from a BindSet call from a Module.
+//
+// Elements are injected in the order they were added, with the elements
+// of modules passed first to NewInjector coming first, and the elements
+// of a parent injector coming before those of a child injector.
+type SetBuilder interface {
+	AddSingleton(singleton interface{})
+	AddConstructor(constructor interface{})
+	AddSingletonConstructor(constructor interface{})
+	AddTaggedConstructor(constructor interface{})
+	AddTaggedSingletonConstructor(constructor interface{})
+}
+
+// MapBuilder is the return value from a BindMap call from a Module.
+type MapBuilder interface {
+	AddSingleton(key string, singleton interface{})
+	AddConstructor(key string, constructor interface{})
+	AddSingletonConstructor(key string, constructor interface{})
+	AddTaggedConstructor(key string, constructor interface{})
+	AddTaggedSingletonConstructor(key string, constructor interface{})
 }
 
 // InterfaceBuilder is the return value when binding an interface from a Module.
 type InterfaceBuilder interface {
 	Builder
+	// To links the interface to the binding of to, which can be declared in any
+	// Module of the Injector or of a parent injector. to can also be a pointer
+	// to another interface that is linked in turn.
 	To(to interface{})
+	// ToTagged links the interface to the tagged binding of to.
+	ToTagged(tag string, to interface{})
 }
 
 // SingletonBuilder is returned when binding a singleton constructor.
 type SingletonBuilder interface {
 	// Eagerly creates the singleton (by calling its constructor) right after
-	// creation of the injector.
+	// creation of the injector, for every binding key it is bound to.
 	Eagerly()
 
 	// EagerlyAndCall creates the singleton eagerly as with Eagerly() above,
@@ -434,14 +917,31 @@ type SingletonBuilder interface {
 	// example, set a global variable (a "traditional singleton") with the
 	// created singleton instance. This can be useful when integrating
 	// 3rd-party libraries that rely on such singletons. Use with caution!
+	// The function is called as with Injector.CallTagged if it takes an
+	// anonymous struct, so that it can get tagged values.
 	EagerlyAndCall(function interface{})
 }
 
+// Scope controls how values created by a constructor are shared, see Builder.In.
+type Scope interface {
+	// Scope is called once for every scoped binding of an Injector. The unscoped
+	// function calls the constructor of the binding, and the returned function is
+	// called instead of it whenever a value is needed, deciding when to construct
+	// new values.
+	Scope(unscoped func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error)
+}
+
 // Injector provides your dependencies.
 type Injector interface {
 	fmt.Stringer
 	Get(from interface{}) (interface{}, error)
 	GetTagged(tag string, from interface{}) (interface{}, error)
+	// GetContext is like Get, but passes ctx to constructors that take a
+	// context.Context as their first parameter.
+	GetContext(ctx context.Context, from interface{}) (interface{}, error)
+	// GetTaggedContext is like GetTagged, but passes ctx to constructors that
+	// take a context.Context as their first parameter.
+	GetTaggedContext(ctx context.Context, tag string, from interface{}) (interface{}, error)
 	GetTaggedBool(tag string) (bool, error)
 	GetTaggedInt(tag string) (int, error)
 	GetTaggedInt8(tag string) (int8, error)
@@ -458,8 +958,25 @@ type Injector interface {
 	GetTaggedComplex64(tag string) (complex64, error)
 	GetTaggedComplex128(tag string) (complex128, error)
 	GetTaggedString(tag string) (string, error)
+	GetTaggedDuration(tag string) (time.Duration, error)
+	GetTaggedTime(tag string) (time.Time, error)
+	GetTaggedStrings(tag string) ([]string, error)
+	GetTaggedURL(tag string) (*url.URL, error)
+	GetTaggedIP(tag string) (net.IP, error)
+	// GetTaggedConstant gets a constant bound with Module.BindTaggedConstant, where
+	// from is a value of its type.
+	GetTaggedConstant(tag string, from interface{}) (interface{}, error)
 	Call(function interface{}) ([]interface{}, error)
 	CallTagged(taggedFunction interface{}) ([]interface{}, error)
+	// CallWith calls function with the given arguments for the parameters they
+	// are assignable to, in order, and injects all other parameters.
+	CallWith(function interface{}, arguments ...interface{}) ([]interface{}, error)
+	// CallContext is like Call, but passes ctx to the function and to
+	// constructors that take a context.Context as their first parameter.
+	CallContext(ctx context.Context, function interface{}) ([]interface{}, error)
+	// CallTaggedContext is like CallTagged, but passes ctx to constructors
+	// that take a context.Context as their first parameter.
+	CallTaggedContext(ctx context.Context, taggedFunction interface{}) ([]interface{}, error)
 	Populate(populateStruct interface{}) error
 
 	// NewChildInjector creates a child injector for the specified modules. The
@@ -468,10 +985,50 @@ type Injector interface {
 	// attempt to redefine bindings of the parent injector in child modules will
 	// result in an error.
 	NewChildInjector(modules ...Module) (Injector, error)
+
+	// Close stops all singletons constructed by this injector that implement
+	// Stopper or io.Closer, in the reverse order of their construction. All
+	// singletons are stopped even if some fail, and the errors are returned
+	// together, each as an ErrCloseErrors with the error of the singleton as
+	// Cause. Singletons of a parent injector are not stopped when closing a
+	// child injector.
+	Close(ctx context.Context) error
+	// Bindings returns the bindings of the Injector, including those of its
+	// parent injectors that are not replaced by the Injector, sorted by key.
+	Bindings() []BindingInfo
+}
+
+// Initializer can be implemented by constructed values that need to be
+// initialized after construction. Init is called once for a singleton, and
+// every time a value is constructed for other bindings. If Init returns an
+// error or panics, getting the value fails with ErrInitFailed.
+type Initializer interface {
+	Init(ctx context.Context) error
+}
+
+// Stopper can be implemented by singletons that need to release resources when
+// the Injector is closed. If a singleton implements both Stopper and io.Closer,
+// only Stop is called.
+type Stopper interface {
+	Stop(ctx context.Context) error
 }
 
 // NewInjector creates a new Injector for the specified Modules.
 //
 // Note that Modules are not thread-safe, it is your responsibility to make sure
 // all Modules have all bindings in place before passing them as parameters to NewInjector.
-func NewInjector(modules ...Module) (Injector, error) { return newInjector(modules) }
+func NewInjector(modules ...Module) (Injector, error) {
+	return newInjector(context.Background(), Options{}, modules)
+}
+
+// NewInjectorContext is like NewInjector, but passes ctx to the constructors
+// of eager singletons that take a context.Context as their first parameter.
+func NewInjectorContext(ctx context.Context, modules ...Module) (Injector, error) {
+	return newInjector(ctx, Options{}, modules)
+}
+
+// NewInjectorWithOptions is like NewInjector, but creates the Injector with the
+// specified Options.
+func NewInjectorWithOptions(options Options, modules ...Module) (Injector, error) {
+	return newInjector(context.Background(), options, modules)
+}
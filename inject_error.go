@@ -1,7 +1,9 @@
 package inject
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -14,6 +16,7 @@ const (
 	injectErrorTypeIntermediateBinding            = "Trying to get for an intermediate binding"
 	injectErrorTypeFinalBinding                   = "Trying to get bindingKey for a final binding"
 	injectErrorTypeCannotCastModule               = "Cannot cast Module to internal module type"
+	injectErrorTypeCannotCastInjector             = "Cannot cast Injector to internal injector type"
 	injectErrorTypeNoBinding                      = "No binding for binding key"
 	injectErrorTypeNoFinalBinding                 = "No final binding for binding key"
 	injectErrorTypeAlreadyBound                   = "Already found a binding for this binding key"
@@ -23,75 +26,187 @@ const (
 	injectErrorTypeNotInterfacePtr                = "Value is not an interface pointer"
 	injectErrorTypeNotStructPtr                   = "Value is not a struct pointer"
 	injectErrorTypeNotSupportedBindType           = "Type is not supported for this binding method"
-	injectErrorTypeBindingErrors                  = "Errors with bindings"
+	injectErrorTypeDependencyCycle                = "Dependency cycle between bindings"
+	injectErrorTypeCloseErrors                    = "Errors closing singletons"
+	injectErrorTypeScopeNotSupported              = "Scope not supported for this binding method"
+	injectErrorTypeOutOfScope                     = "Context is not within the scope of the binding"
+	injectErrorTypeDuplicateMapKey                = "Already found a map entry for this key"
+	injectErrorTypeUnknownTagOption               = "Unknown option in inject struct tag"
+	injectErrorTypeArgumentNotUsed                = "Argument not assignable to any parameter of the function"
+	injectErrorTypeFactoryInvalid                 = "Factory must take at least one parameter and return a value and an error"
+	injectErrorTypeConstructorFailed              = "Constructor returned an error"
+	injectErrorTypeConstructorPanicked            = "Constructor panicked"
+	injectErrorTypePrivateBinding                 = "Binding for binding key is private to a private module"
+	injectErrorTypeNoProviderMethod               = "No provider method for the provider options"
+	injectErrorTypeInitFailed                     = "Initializing a constructed value returned an error"
+	injectErrorTypeOnConstructInvalid             = "OnConstruct function must take the value, optionally after a context.Context, and return an error"
+	injectErrorTypeOnConstructNotSupported        = "OnConstruct not supported for this binding method"
 )
 
+// The errors of this package, to be used with errors.Is. All errors
+// returned are an *Error created from one of these.
 var (
-	errNil                            = newInjectError(injectErrorTypeNil)
-	errReflectTypeNil                 = newInjectError(injectErrorTypeReflectTypeNil)
-	errNotSupportedYet                = newInjectError(injectErrorTypeNotSupportedYet)
-	errNotAssignable                  = newInjectError(injectErrorTypeNotAssignable)
-	errConstructorReturnValuesInvalid = newInjectError(injectErrorTypeConstructorReturnValuesInvalid)
-	errIntermediateBinding            = newInjectError(injectErrorTypeIntermediateBinding)
-	errFinalBinding                   = newInjectError(injectErrorTypeFinalBinding)
-	errCannotCastModule               = newInjectError(injectErrorTypeCannotCastModule)
-	errNoBinding                      = newInjectError(injectErrorTypeNoBinding)
-	errNoFinalBinding                 = newInjectError(injectErrorTypeNoFinalBinding)
-	errAlreadyBound                   = newInjectError(injectErrorTypeAlreadyBound)
-	errTagEmpty                       = newInjectError(injectErrorTypeTagEmpty)
-	errTaggedParametersInvalid        = newInjectError(injectErrorTypeTaggedParametersInvalid)
-	errNotFunction                    = newInjectError(injectErrorTypeNotFunction)
-	errNotInterfacePtr                = newInjectError(injectErrorTypeNotInterfacePtr)
-	errNotStructPtr                   = newInjectError(injectErrorTypeNotStructPtr)
-	errNotSupportedBindType           = newInjectError(injectErrorTypeNotSupportedBindType)
-	errBindingErrors                  = newInjectError(injectErrorTypeBindingErrors)
+	ErrNil                            = newError(injectErrorTypeNil)
+	errReflectTypeNil                 = newError(injectErrorTypeReflectTypeNil)
+	ErrNotSupportedYet                = newError(injectErrorTypeNotSupportedYet)
+	ErrNotAssignable                  = newError(injectErrorTypeNotAssignable)
+	ErrConstructorReturnValuesInvalid = newError(injectErrorTypeConstructorReturnValuesInvalid)
+	errIntermediateBinding            = newError(injectErrorTypeIntermediateBinding)
+	errFinalBinding                   = newError(injectErrorTypeFinalBinding)
+	ErrCannotCastModule               = newError(injectErrorTypeCannotCastModule)
+	ErrCannotCastInjector             = newError(injectErrorTypeCannotCastInjector)
+	ErrNoBinding                      = newError(injectErrorTypeNoBinding)
+	ErrNoFinalBinding                 = newError(injectErrorTypeNoFinalBinding)
+	ErrAlreadyBound                   = newError(injectErrorTypeAlreadyBound)
+	ErrTagEmpty                       = newError(injectErrorTypeTagEmpty)
+	ErrTaggedParametersInvalid        = newError(injectErrorTypeTaggedParametersInvalid)
+	ErrNotFunction                    = newError(injectErrorTypeNotFunction)
+	errNotInterfacePtr                = newError(injectErrorTypeNotInterfacePtr)
+	ErrNotStructPtr                   = newError(injectErrorTypeNotStructPtr)
+	ErrNotSupportedBindType           = newError(injectErrorTypeNotSupportedBindType)
+	ErrDependencyCycle                = newError(injectErrorTypeDependencyCycle)
+	ErrCloseErrors                    = newError(injectErrorTypeCloseErrors)
+	ErrScopeNotSupported              = newError(injectErrorTypeScopeNotSupported)
+	ErrOutOfScope                     = newError(injectErrorTypeOutOfScope)
+	ErrDuplicateMapKey                = newError(injectErrorTypeDuplicateMapKey)
+	ErrUnknownTagOption               = newError(injectErrorTypeUnknownTagOption)
+	ErrArgumentNotUsed                = newError(injectErrorTypeArgumentNotUsed)
+	ErrFactoryInvalid                 = newError(injectErrorTypeFactoryInvalid)
+	ErrConstructorFailed              = newError(injectErrorTypeConstructorFailed)
+	ErrConstructorPanicked            = newError(injectErrorTypeConstructorPanicked)
+	ErrPrivateBinding                 = newError(injectErrorTypePrivateBinding)
+	ErrNoProviderMethod               = newError(injectErrorTypeNoProviderMethod)
+	ErrInitFailed                     = newError(injectErrorTypeInitFailed)
+	ErrOnConstructInvalid             = newError(injectErrorTypeOnConstructInvalid)
+	ErrOnConstructNotSupported        = newError(injectErrorTypeOnConstructNotSupported)
 )
 
-type injectError struct {
+// Error is the type of all errors of this package, and is returned either
+// directly or wrapped. Use errors.Is with the Err variables to check for a
+// specific kind of error, and errors.As to get the Error.
+type Error struct {
 	errorType string
-	tags      injectErrorTags
+	// the sentinel error this error was created from, nil for sentinel errors
+	sentinel *Error
+	// The key of the binding the error is about, the zero Key if none.
+	Key Key
+	// Additional context about the error.
+	Tags []ErrorTag
+	// The error that caused this error, such as the error returned by a
+	// constructor, or nil.
+	Cause error
 }
 
-func newInjectError(errorType string) *injectError {
-	return &injectError{errorType, make([]*injectErrorTag, 0)}
+// ErrorTag is additional context about an Error.
+type ErrorTag struct {
+	Name  string
+	Value interface{}
 }
 
-func (i *injectError) Error() string {
-	value := fmt.Sprintf("inject: %s", i.errorType)
-	if len(i.tags) == 0 {
-		return value
+func newError(errorType string) *Error {
+	return &Error{errorType: errorType}
+}
+
+func (e *Error) Error() string {
+	value := fmt.Sprintf("inject: %s", e.errorType)
+	if len(e.Tags) != 0 {
+		value = fmt.Sprintf("%s %s", value, errorTagsString(e.Tags))
+	}
+	if e.Cause != nil {
+		value = fmt.Sprintf("%s: %s", value, e.Cause.Error())
 	}
-	return fmt.Sprintf("%s %s", value, i.tags.String())
+	return value
+}
+
+// Is returns true if target is the sentinel error this error was created from.
+func (e *Error) Is(target error) bool {
+	return e.sentinel != nil && target == e.sentinel
+}
+
+// Unwrap returns the cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func (e *Error) withTag(name string, value interface{}) *Error {
+	err := e.copy()
+	err.Tags = append(err.Tags, ErrorTag{name, value})
+	return err
 }
 
-func (i *injectError) withTag(key string, value interface{}) *injectError {
-	return &injectError{i.errorType, append(i.tags, newInjectErrorTag(key, value))}
+func (e *Error) withBindingKey(bindingKey bindingKey) *Error {
+	err := e.withTag("bindingKey", bindingKey)
+	err.Key = newKey(bindingKey)
+	return err
 }
 
-type injectErrorTag struct {
-	key   string
-	value interface{}
+func (e *Error) withCause(cause error) *Error {
+	err := e.copy()
+	err.Cause = cause
+	return err
 }
 
-func newInjectErrorTag(key string, value interface{}) *injectErrorTag {
-	return &injectErrorTag{key, value}
+func (e *Error) copy() *Error {
+	sentinel := e.sentinel
+	if sentinel == nil {
+		sentinel = e
+	}
+	return &Error{e.errorType, sentinel, e.Key, append([]ErrorTag(nil), e.Tags...), e.Cause}
 }
 
-func (t *injectErrorTag) String() string {
-	if stringer, ok := t.value.(fmt.Stringer); ok {
-		return fmt.Sprintf("%s:%s", t.key, stringer.String())
+// joinErrors returns nil for no errors, the error itself for one error, and
+// the errors sorted by the key of their binding joined with errors.Join otherwise
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
 	}
-	return fmt.Sprintf("%s:%s", t.key, t.value)
+	sorted := append([]error(nil), errs...)
+	sort.SliceStable(sorted, func(i int, j int) bool {
+		iKey, jKey := errorKeyString(sorted[i]), errorKeyString(sorted[j])
+		if iKey != jKey {
+			return iKey < jKey
+		}
+		return sorted[i].Error() < sorted[j].Error()
+	})
+	return errors.Join(sorted...)
 }
 
-type injectErrorTags []*injectErrorTag
+// splitErrors returns the errors joined by errors.Join, or err itself
+func splitErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joinedErr, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, err := range joinedErr.Unwrap() {
+			errs = append(errs, splitErrors(err)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
 
-func (ts injectErrorTags) String() string {
-	if len(ts) == 0 {
-		return ""
+func errorKeyString(err error) string {
+	var injectErr *Error
+	if errors.As(err, &injectErr) && injectErr.Key.Type != nil {
+		return injectErr.Key.String()
 	}
-	s := make([]string, len(ts))
-	for i, tag := range ts {
+	return ""
+}
+
+func (t ErrorTag) String() string {
+	if stringer, ok := t.Value.(fmt.Stringer); ok {
+		return fmt.Sprintf("%s:%s", t.Name, stringer.String())
+	}
+	return fmt.Sprintf("%s:%v", t.Name, t.Value)
+}
+
+func errorTagsString(tags []ErrorTag) string {
+	s := make([]string, len(tags))
+	for i, tag := range tags {
 		s[i] = tag.String()
 	}
 	return fmt.Sprintf("tags{%s}", strings.Join(s, " "))
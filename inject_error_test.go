@@ -0,0 +1,59 @@
+package inject
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorIs(t *testing.T) {
+	injector, err := NewInjector()
+	require.NoError(t, err)
+	_, err = injector.Get((*SimpleInterface)(nil))
+	require.True(t, errors.Is(err, ErrNoBinding))
+	require.False(t, errors.Is(err, ErrAlreadyBound))
+	var injectErr *Error
+	require.True(t, errors.As(err, &injectErr))
+	require.Equal(t, Key{simpleInterfaceReflectType, ""}, injectErr.Key)
+	require.Nil(t, injectErr.Cause)
+
+	module := NewModule()
+	module.BindTagged("tagOne", (*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"one"})
+	module.BindTagged("tagOne", (*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"two"})
+	_, err = NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeAlreadyBound)
+}
+
+func TestErrorWithTagDoesNotModifySentinel(t *testing.T) {
+	err := ErrNoBinding.withTag("one", "1")
+	other := err.withTag("two", "2")
+	_ = err.withTag("three", "3")
+	require.Empty(t, ErrNoBinding.Tags)
+	require.Equal(t, []ErrorTag{{"one", "1"}, {"two", "2"}}, other.Tags)
+	require.True(t, errors.Is(other, ErrNoBinding))
+	require.Equal(t, "inject: No binding for binding key tags{one:1 two:2}", other.Error())
+}
+
+func TestErrorCause(t *testing.T) {
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToConstructor(func() (BarInterface, error) {
+		return nil, errXYZ
+	})
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	_, err = injector.Get((*BarInterface)(nil))
+	require.True(t, errors.Is(err, ErrConstructorFailed))
+	require.True(t, errors.Is(err, errXYZ))
+	require.Contains(t, err.Error(), ": XYZ")
+}
+
+func TestModuleAddError(t *testing.T) {
+	module := NewModule()
+	module.AddError(errXYZ)
+	module.AddError(nil)
+	_, err := NewInjector(module)
+	require.True(t, errors.Is(err, errXYZ))
+	require.True(t, errors.Is(err, ErrNil))
+}
@@ -3,6 +3,8 @@ package inject
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"sync/atomic"
 	"testing"
 
@@ -155,6 +157,59 @@ func TestSimplePtrStructIndirectFailsWhenNoFinalBinding(t *testing.T) {
 	require.Contains(t, err.Error(), injectErrorTypeNoFinalBinding)
 }
 
+func TestIndirectAcrossModulesAndParent(t *testing.T) {
+	module := NewModule()
+	module.BindInterface((*SimpleInterface)(nil)).To(&SimplePtrStruct{})
+	otherModule := NewModule()
+	otherModule.Bind(&SimplePtrStruct{}).ToSingletonConstructor(func() *SimplePtrStruct { return &SimplePtrStruct{"hello"} })
+	injector, err := NewInjector(module, otherModule)
+	require.NoError(t, err)
+	object, err := injector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "hello", object.(SimpleInterface).Foo())
+	// the link gets the singleton of the final binding
+	ptr, err := injector.Get(&SimplePtrStruct{})
+	require.NoError(t, err)
+	require.True(t, object == ptr)
+
+	parentInjector, err := NewInjector(otherModule)
+	require.NoError(t, err)
+	childInjector, err := parentInjector.NewChildInjector(module)
+	require.NoError(t, err)
+	object, err = childInjector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "hello", object.(SimpleInterface).Foo())
+}
+
+type EmbeddingSimpleInterface interface {
+	SimpleInterface
+}
+
+func TestIndirectChainAndTagged(t *testing.T) {
+	module := NewModule()
+	module.BindInterface((*SimpleInterface)(nil)).To((*EmbeddingSimpleInterface)(nil))
+	module.BindInterface((*EmbeddingSimpleInterface)(nil)).ToTagged("tagOne", &SimplePtrStruct{})
+	module.BindTagged("tagOne", &SimplePtrStruct{}).ToSingleton(&SimplePtrStruct{"hello"})
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	object, err := injector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "hello", object.(SimpleInterface).Foo())
+
+	module = NewModule()
+	module.BindInterface((*SimpleInterface)(nil)).ToTagged("", &SimplePtrStruct{})
+	_, err = NewInjector(module)
+	require.True(t, errors.Is(err, ErrTagEmpty))
+}
+
+func TestIndirectCycle(t *testing.T) {
+	module := NewModule()
+	module.BindInterface((*SimpleInterface)(nil)).To((*EmbeddingSimpleInterface)(nil))
+	module.BindInterface((*EmbeddingSimpleInterface)(nil)).To((*EmbeddingSimpleInterface)(nil))
+	_, err := NewInjector(module)
+	require.True(t, errors.Is(err, ErrDependencyCycle))
+}
+
 // ***** simple BindTagged tests *****
 
 func TestTaggedTagEmpty(t *testing.T) {
@@ -405,9 +460,41 @@ func TestMultipleBindingErrors(t *testing.T) {
 	module.Bind((*BarInterface)(nil)).ToSingleton(BarPtrStruct{1})
 	_, err := NewInjector(module)
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "1:inject:")
-	require.Contains(t, err.Error(), "2:inject:")
-	require.Contains(t, err.Error(), injectErrorTypeNotAssignable)
+	require.Equal(t, 2, countErrors(err, ErrNotAssignable))
+}
+
+func TestMultipleMissingBindings(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).ToConstructor(createSecondInterface)
+	module.BindTagged("tagOne", (*SecondInterface)(nil)).ToConstructor(createSecondInterface)
+	module.Bind((*BarInterface)(nil)).ToSingleton(BarPtrStruct{1})
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	errs := splitErrors(err)
+	// BarInterface is declared, so it is only reported as not assignable
+	require.Len(t, errs, 3)
+	require.True(t, errors.Is(errs[0], ErrNotAssignable))
+	for _, err := range errs[1:] {
+		require.True(t, errors.Is(err, ErrNoBinding))
+	}
+	// sorted by the missing binding key
+	var injectErr *Error
+	require.True(t, errors.As(errs[0], &injectErr))
+	require.Equal(t, Key{barInterfaceReflectType, ""}, injectErr.Key)
+	require.True(t, errors.As(errs[1], &injectErr))
+	require.Equal(t, Key{simpleInterfaceReflectType, ""}, injectErr.Key)
+	require.Contains(t, injectErr.Error(), "requiredBy")
+}
+
+// countErrors returns the number of errors joined in err that match target
+func countErrors(err error, target error) int {
+	count := 0
+	for _, err := range splitErrors(err) {
+		if errors.Is(err, target) {
+			count++
+		}
+	}
+	return count
 }
 
 func TestConstructorSimple(t *testing.T) {
@@ -434,7 +521,10 @@ func TestConstructorErrReturned(t *testing.T) {
 	for _, injector := range createInjectors(t, module) {
 		t.Run(injector.name, func(t *testing.T) {
 			_, err := injector.Get((*SecondInterface)(nil))
-			require.Equal(t, "XYZ", err.Error())
+			require.True(t, errors.Is(err, ErrConstructorFailed))
+			var injectErr *Error
+			require.True(t, errors.As(err, &injectErr))
+			require.Equal(t, "XYZ", injectErr.Cause.Error())
 		})
 	}
 }
@@ -538,7 +628,9 @@ func TestSingletonConstructorWithEvilCounterErr(t *testing.T) {
 			}
 			for i := 0; i < goRoutineIterations; i++ {
 				barInterfaceErr := <-evilChan
-				require.Equal(t, "XYZ 1", barInterfaceErr.err.Error())
+				var injectErr *Error
+				require.True(t, errors.As(barInterfaceErr.err, &injectErr))
+				require.Equal(t, "XYZ 1", injectErr.Cause.Error())
 			}
 
 			close(evilChan)
@@ -911,6 +1003,73 @@ func TestBindTaggedConstantPopulate(t *testing.T) {
 	}
 }
 
+type Port int
+
+type PopulateStructNamedConstants struct {
+	Port    Port          `inject:"port"`
+	Timeout time.Duration `inject:"timeout"`
+	Hosts   []string      `inject:"hosts"`
+	URL     *url.URL      `inject:"url"`
+	IP      net.IP        `inject:"ip"`
+}
+
+func TestBindTaggedConstantNamedTypes(t *testing.T) {
+	u, err := url.Parse("https://example.com")
+	require.NoError(t, err)
+	now := time.Now()
+	module := NewModule()
+	module.BindTaggedConstant("port", Port(0)).ToSingleton(Port(8080))
+	module.BindTaggedDuration("timeout").ToSingleton(time.Second)
+	module.BindTaggedTime("now").ToSingleton(now)
+	module.BindTaggedStrings("hosts").ToSingleton([]string{"one", "two"})
+	module.BindTaggedURL("url").ToSingleton(u)
+	module.BindTaggedIP("ip").ToSingleton(net.IPv4(127, 0, 0, 1))
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			port, err := injector.GetTaggedConstant("port", Port(0))
+			require.NoError(t, err)
+			require.Equal(t, Port(8080), port)
+			timeout, err := injector.GetTaggedDuration("timeout")
+			require.NoError(t, err)
+			require.Equal(t, time.Second, timeout)
+			value, err := injector.GetTaggedTime("now")
+			require.NoError(t, err)
+			require.Equal(t, now, value)
+			hosts, err := injector.GetTaggedStrings("hosts")
+			require.NoError(t, err)
+			require.Equal(t, []string{"one", "two"}, hosts)
+			value2, err := injector.GetTaggedURL("url")
+			require.NoError(t, err)
+			require.Equal(t, u, value2)
+			ip, err := injector.GetTaggedIP("ip")
+			require.NoError(t, err)
+			require.Equal(t, net.IPv4(127, 0, 0, 1), ip)
+
+			populateStruct := PopulateStructNamedConstants{}
+			require.NoError(t, injector.Populate(&populateStruct))
+			require.Equal(t, PopulateStructNamedConstants{8080, time.Second, []string{"one", "two"}, u, net.IPv4(127, 0, 0, 1)}, populateStruct)
+
+			// an int is not a Port
+			_, err = injector.GetTaggedInt("port")
+			require.True(t, errors.Is(err, ErrNoBinding))
+		})
+	}
+}
+
+func TestBindTaggedConstantNotSupported(t *testing.T) {
+	module := NewModule()
+	module.BindTaggedConstant("struct", SimpleStruct{})
+	module.BindTaggedConstant("nil", nil)
+	_, err := NewInjector(module)
+	require.Equal(t, 1, countErrors(err, ErrNotSupportedBindType))
+	require.Equal(t, 1, countErrors(err, ErrNil))
+
+	injector, err := NewInjector()
+	require.NoError(t, err)
+	_, err = injector.GetTaggedConstant("struct", SimpleStruct{})
+	require.True(t, errors.Is(err, ErrNotSupportedBindType))
+}
+
 func newSimpleStructFromTaggedStringAnonymousStruct(p struct {
 	Time time.Time
 	Val  string `inject:"tag_one"`
@@ -971,9 +1130,7 @@ func TestOverrideErrorsInSource(t *testing.T) {
 
 	_, err := NewInjector(Override(module).With(override))
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "1:inject:")
-	require.Contains(t, err.Error(), "2:inject:")
-	require.Contains(t, err.Error(), injectErrorTypeNotAssignable)
+	require.Equal(t, 2, countErrors(err, ErrNotAssignable))
 }
 
 func TestOverrideErrorsInOverride(t *testing.T) {
@@ -987,9 +1144,7 @@ func TestOverrideErrorsInOverride(t *testing.T) {
 
 	_, err := NewInjector(Override(module).With(override))
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "1:inject:")
-	require.Contains(t, err.Error(), "2:inject:")
-	require.Contains(t, err.Error(), injectErrorTypeNotAssignable)
+	require.Equal(t, 2, countErrors(err, ErrNotAssignable))
 }
 
 func TestOverrideErrorsInBoth(t *testing.T) {
@@ -1003,9 +1158,7 @@ func TestOverrideErrorsInBoth(t *testing.T) {
 
 	_, err := NewInjector(Override(module).With(override))
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "1:inject:")
-	require.Contains(t, err.Error(), "2:inject:")
-	require.Contains(t, err.Error(), injectErrorTypeNotAssignable)
+	require.Equal(t, 2, countErrors(err, ErrNotAssignable))
 }
 
 func TestInstall(t *testing.T) {
@@ -1042,8 +1195,7 @@ func TestInstallDuplicateBinding(t *testing.T) {
 
 	_, err := NewInjector(module)
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "1:inject:")
-	require.Contains(t, err.Error(), injectErrorTypeAlreadyBound)
+	require.Equal(t, 1, countErrors(err, ErrAlreadyBound))
 }
 
 func TestInstallErrorsInSource(t *testing.T) {
@@ -1058,8 +1210,7 @@ func TestInstallErrorsInSource(t *testing.T) {
 
 	_, err := NewInjector(module)
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "1:inject:")
-	require.Contains(t, err.Error(), injectErrorTypeNotAssignable)
+	require.Equal(t, 1, countErrors(err, ErrNotAssignable))
 }
 
 func TestInstallErrorsInTarget(t *testing.T) {
@@ -1074,8 +1225,7 @@ func TestInstallErrorsInTarget(t *testing.T) {
 
 	_, err := NewInjector(module)
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "1:inject:")
-	require.Contains(t, err.Error(), injectErrorTypeNotAssignable)
+	require.Equal(t, 1, countErrors(err, ErrNotAssignable))
 }
 
 func TestInstallErrorsInBoth(t *testing.T) {
@@ -1090,9 +1240,63 @@ func TestInstallErrorsInBoth(t *testing.T) {
 
 	_, err := NewInjector(module)
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "1:inject:")
-	require.Contains(t, err.Error(), "2:inject:")
-	require.Contains(t, err.Error(), injectErrorTypeNotAssignable)
+	require.Equal(t, 2, countErrors(err, ErrNotAssignable))
+}
+
+type barConfigurer struct {
+	bar int
+}
+
+func (b barConfigurer) Configure(binder Binder) {
+	binder.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{b.bar})
+}
+
+// countingModule is a Module implementation that installs another Module
+type countingModule struct {
+	Module
+	configured int
+}
+
+func (c *countingModule) Configure(binder Binder) {
+	c.configured++
+	binder.Install(c.Module)
+}
+
+func TestConfiguredModule(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.Bind((*SecondInterface)(nil)).ToConstructor(createSecondInterface)
+	module.Install(NewConfiguredModule(barConfigurer{1}))
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			object, err := injector.Get((*SecondInterface)(nil))
+			require.NoError(t, err)
+			require.Equal(t, 1, object.(SecondInterface).Bar().Bar())
+		})
+	}
+}
+
+func TestCustomModule(t *testing.T) {
+	wrapped := NewModule()
+	wrapped.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module := &countingModule{Module: wrapped}
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	object, err := injector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "hello", object.(SimpleInterface).Foo())
+	require.Equal(t, 1, module.configured)
+
+	override := NewModule()
+	override.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"override"})
+	installing := NewModule()
+	installing.Install(Override(module).With(override))
+	injector, err = NewInjector(installing)
+	require.NoError(t, err)
+	object, err = injector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "override", object.(SimpleInterface).Foo())
+	require.Equal(t, 2, module.configured)
 }
 
 func callWithSecondInterface(s SecondInterface) SecondInterface {
@@ -1192,6 +1396,12 @@ func callAndIncrement(s SimpleInterface) {
 	callAndIncrementSimple = s
 }
 
+func callTaggedAndIncrement(str struct {
+	Simple SimpleInterface `inject:"tagOne"`
+}) {
+	callAndIncrement(str.Simple)
+}
+
 type moduleType int
 
 const (
@@ -1230,6 +1440,20 @@ func TestEagerSingletons(t *testing.T) {
 			singletonBuilder.EagerlyAndCall(callAndIncrement)
 			return module
 		}, false},
+		{"BindTagged.ToSingletonConstructor.Eagerly", func() Module {
+			module := NewModule()
+			singletonBuilder := module.BindTagged("tagOne", (*SimpleInterface)(nil)).ToSingletonConstructor(createSimpleInterfaceAndCount)
+			singletonBuilder.Eagerly()
+			return module
+		}, true},
+		{"BindTagged.ToTaggedSingletonConstructor.EagerlyAndCall", func() Module {
+			module := NewModule()
+			singletonBuilder := module.BindTagged("tagOne", (*SimpleInterface)(nil)).ToTaggedSingletonConstructor(func(struct{}) SimpleInterface {
+				return createSimpleInterfaceAndCount()
+			})
+			singletonBuilder.EagerlyAndCall(callTaggedAndIncrement)
+			return module
+		}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1269,6 +1493,25 @@ func doEagerSingletons(t *testing.T, creator func() Module, eagerly bool, modTyp
 	}
 }
 
+func TestEagerSingletonMultipleBindingKeys(t *testing.T) {
+	count := 0
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil), &SimplePtrStruct{}).ToSingletonConstructor(func() *SimplePtrStruct {
+		count++
+		return &SimplePtrStruct{"hello"}
+	}).Eagerly()
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	// both binding keys share the singleton
+	simpleInterface, err := injector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	simplePtrStruct, err := injector.Get(&SimplePtrStruct{})
+	require.NoError(t, err)
+	require.True(t, simpleInterface == simplePtrStruct)
+	require.Equal(t, 1, count)
+}
+
 func TestBindBasicTypes(t *testing.T) {
 	module := NewModule()
 
@@ -1492,6 +1735,80 @@ func TestInjectorString(t *testing.T) {
 	}
 }
 
+// ***** cycle tests *****
+
+type CycleA struct {
+	b *CycleB
+}
+
+type CycleB struct {
+	a *CycleA
+}
+
+type CycleC struct {
+	a *CycleA
+}
+
+func createCycleA(b *CycleB) (*CycleA, error) {
+	return &CycleA{b}, nil
+}
+
+func createCycleB(a *CycleA) (*CycleB, error) {
+	return &CycleB{a}, nil
+}
+
+func createCycleBTagged(s struct {
+	A *CycleA `inject:"tagOne"`
+}) (*CycleB, error) {
+	return &CycleB{s.A}, nil
+}
+
+func createCycleC(a *CycleA) (*CycleC, error) {
+	return &CycleC{a}, nil
+}
+
+func TestDependencyCycle(t *testing.T) {
+	module := NewModule()
+	module.Bind(&CycleA{}).ToConstructor(createCycleA)
+	module.Bind(&CycleB{}).ToConstructor(createCycleB)
+	module.Bind(&CycleC{}).ToConstructor(createCycleC)
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeDependencyCycle)
+	require.Contains(t, err.Error(), "{type:*inject.CycleA} -> {type:*inject.CycleB} -> {type:*inject.CycleA}")
+}
+
+func TestDependencyCycleThroughSingleton(t *testing.T) {
+	module := NewModule()
+	module.Bind(&CycleA{}).ToSingletonConstructor(createCycleA)
+	module.Bind(&CycleB{}).ToSingletonConstructor(createCycleB)
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "{type:*inject.CycleA} -> {type:*inject.CycleB} -> {type:*inject.CycleA}")
+}
+
+func TestDependencyCycleThroughTaggedConstructor(t *testing.T) {
+	module := NewModule()
+	module.BindTagged("tagOne", &CycleA{}).ToConstructor(createCycleA)
+	module.Bind(&CycleB{}).ToTaggedConstructor(createCycleBTagged)
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "{type:*inject.CycleA tag:tagOne} -> {type:*inject.CycleB} -> {type:*inject.CycleA tag:tagOne}")
+}
+
+func TestDependencyCycleInChildInjector(t *testing.T) {
+	parentModule := NewModule()
+	parentModule.Bind(&CycleC{}).ToConstructor(func() (*CycleC, error) { return &CycleC{}, nil })
+	parent, err := NewInjector(parentModule)
+	require.NoError(t, err)
+	module := NewModule()
+	module.Bind(&CycleA{}).ToConstructor(createCycleA)
+	module.Bind(&CycleB{}).ToConstructor(createCycleB)
+	_, err = parent.NewChildInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeDependencyCycle)
+}
+
 // createInjectors creates three equivalent injectors:
 // * a regular injector based on the given module
 // * a child injector where all bindings are in the parent and
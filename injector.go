@@ -1,10 +1,14 @@
 package inject
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"reflect"
-	"strconv"
 	"strings"
+	"time"
 )
 
 var injectorReflectType = reflect.TypeOf((*Injector)(nil))
@@ -14,43 +18,163 @@ type injector struct {
 	parent *injector
 	// resolved bindings
 	bindings map[bindingKey]resolvedBinding
+	// singletons constructed by this injector, in construction order
+	constructed *constructedSingletons
+	// the module every binding was declared in
+	declaringModules map[bindingKey]*module
+	// the injectors of installed private modules, whose parent is this injector
+	privateInjectors []*injector
+	// the options of the injector, shared with its child and private injectors
+	options *Options
+	// the keys of the bindings that could not be installed, which are not
+	// reported again as missing for the bindings that depend on them
+	failedKeys map[Key]bool
+}
+
+// eagerSingleton is an eager singleton with the injector of the module it was bound in
+type eagerSingleton struct {
+	injector *injector
+	*singletonBuilder
+}
+
+// call calls the function of the eager singleton, which is a tagged function if
+// it takes an anonymous struct
+func (e eagerSingleton) call(ctx context.Context) error {
+	if e.isTaggedFunc() {
+		_, err := e.injector.CallTaggedContext(ctx, e.fn)
+		return err
+	}
+	_, err := e.injector.CallContext(ctx, e.fn)
+	return err
+}
+
+// fnDependencies returns the dependencies of the function of the eager singleton
+func (e eagerSingleton) fnDependencies() []dependency {
+	if e.isTaggedFunc() {
+		return getParameterDependenciesForTaggedFunc(reflect.TypeOf(e.fn), e.injector.options.tagKey())
+	}
+	return getParameterDependenciesForFunc(reflect.TypeOf(e.fn))
+}
+
+func (e eagerSingleton) isTaggedFunc() bool {
+	return verifyTaggedFuncParameters(reflect.TypeOf(e.fn)) == nil
+}
+
+// injectorBindingKey is a binding key of the bindings of an injector
+type injectorBindingKey struct {
+	injector   *injector
+	bindingKey bindingKey
+}
+
+func newInjector(ctx context.Context, options Options, modules []Module) (Injector, error) {
+	injector := &injector{nil, make(map[bindingKey]resolvedBinding), newConstructedSingletons(), make(map[bindingKey]*module), nil, &options, nil}
+	return initInjector(ctx, injector, modules)
 }
 
-func newInjector(modules []Module) (Injector, error) {
-	injector := &injector{nil, make(map[bindingKey]resolvedBinding)}
-	return initInjector(injector, modules)
+// newPrivateInjector returns an injector for a private module, which closes its
+// singletons together with its parent
+func newPrivateInjector(parent *injector) *injector {
+	privateInjector := &injector{parent, make(map[bindingKey]resolvedBinding), parent.constructed, make(map[bindingKey]*module), nil, parent.options, nil}
+	parent.privateInjectors = append(parent.privateInjectors, privateInjector)
+	return privateInjector
 }
 
-func initInjector(injector *injector, modules []Module) (Injector, error) {
+func initInjector(ctx context.Context, injector *injector, modules []Module) (Injector, error) {
 	modules = append(modules, createInjectorModule(injector))
-	var eager []*singletonBuilder
-	for _, m := range modules {
-		castModule, ok := m.(*module)
+	castModules := make([]*module, len(modules))
+	for i, m := range modules {
+		castModule, ok := castModule(m)
 		if !ok {
-			return nil, errCannotCastModule
+			return nil, ErrCannotCastModule
 		}
-		if err := installModuleToInjector(injector, castModule); err != nil {
-			return nil, err
-		}
-		eager = append(eager, castModule.eager...)
+		castModules[i] = castModule
+	}
+	eager, errs := installModules(injector, castModules)
+	injector.failedKeys = failedKeys(errs)
+	var singletons []injectorBindingKey
+	if injector.options.Stage == Production {
+		singletons = singletonBindingKeys(injector)
+	}
+	var reachable map[injectorBindingKey]bool
+	if injector.options.SkipUnreachableValidation {
+		reachable = reachableBindingKeys(eager, singletons)
+	}
+	// validate even if installing failed, to report all errors at once, except
+	// for missing bindings that failed to be installed
+	errs = append(errs, validate(injector, reachable)...)
+	if len(errs) > 0 {
+		err := joinErrors(errs)
+		injector.logErrors(err)
+		return nil, err
 	}
-	if err := validate(injector); err != nil {
+	if err := constructEagerSingletons(ctx, injector.options.Stage, eager, singletons); err != nil {
+		injector.logErrors(err)
 		return nil, err
 	}
+	return injector, nil
+}
+
+// constructEagerSingletons constructs the singletons of the Production stage in
+// dependency order, then the eager singletons, calling their functions
+func constructEagerSingletons(ctx context.Context, stage Stage, eager []eagerSingleton, singletons []injectorBindingKey) error {
+	if stage == Tool {
+		return nil
+	}
+	// the constructors get the cancellation and deadline of ctx, which is also
+	// checked between singletons for constructors that do not look at it
+	ctx = context.WithValue(ctx, constructInjectorContextKey{}, true)
+	for _, singleton := range singletons {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := singleton.injector.get(ctx, nil, singleton.bindingKey); err != nil {
+			return err
+		}
+	}
 	for _, e := range eager {
-		// create the singleton
-		_, err := injector.get(newBindingKey(e.t))
-		if err != nil {
-			return nil, err
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// create the singleton for every binding key
+		for _, bindingKey := range e.bindingKeys {
+			if _, err := e.injector.get(ctx, nil, bindingKey); err != nil {
+				return err
+			}
 		}
 		if e.fn != nil {
-			_, err := injector.Call(e.fn)
-			if err != nil {
-				return nil, err
+			if err := e.call(ctx); err != nil {
+				return err
 			}
 		}
 	}
-	return injector, nil
+	return nil
+}
+
+// installModules installs the modules to the injector, and then their private
+// modules to private injectors
+func installModules(injector *injector, modules []*module) ([]eagerSingleton, []error) {
+	var eager []eagerSingleton
+	var errs []error
+	var privateModules []*privateModule
+	for _, module := range modules {
+		errs = append(errs, installModuleToInjector(injector, module)...)
+		for _, e := range module.eager {
+			eager = append(eager, eagerSingleton{injector, e})
+		}
+		privateModules = append(privateModules, module.privateModules...)
+	}
+	// private bindings may only depend on bindings of the injector that were installed,
+	// and are installed before any binding is exposed so they never collide
+	for _, privateModule := range privateModules {
+		privateInjector := newPrivateInjector(injector)
+		privateEager, privateErrs := installModules(privateInjector, []*module{privateModule.module, createInjectorModule(privateInjector).(*module)})
+		eager = append(eager, privateEager...)
+		errs = append(errs, privateErrs...)
+	}
+	for i, privateModule := range privateModules {
+		errs = append(errs, exposeBindings(injector, injector.privateInjectors[i], privateModule.exposed)...)
+	}
+	return eager, errs
 }
 
 func createInjectorModule(injector *injector) Module {
@@ -59,40 +183,191 @@ func createInjectorModule(injector *injector) Module {
 	return m
 }
 
-func installModuleToInjector(injector *injector, module *module) error {
-	numBindingErrors := len(module.bindingErrors)
-	if numBindingErrors > 0 {
-		err := errBindingErrors
-		for i := 0; i < numBindingErrors; i++ {
-			err = err.withTag(strconv.Itoa(i+1), module.bindingErrors[i].Error())
+// installModuleToInjector installs all bindings of the module that can be installed,
+// returning the binding errors of the module and the errors of the other bindings
+func installModuleToInjector(injector *injector, module *module) []error {
+	errs := append([]error(nil), module.bindingErrors...)
+	// a binding for several binding keys, such as Bind(a, b).ToSingletonConstructor(f),
+	// is resolved once, so that the binding keys share its singleton
+	resolvedBindings := make(map[binding]resolvedBinding)
+	for _, bindingKey := range sortedBindingKeys(module.bindings) {
+		if err := installBinding(injector, module, bindingKey, module.bindings[bindingKey], module.declaringModules[bindingKey], resolvedBindings); err != nil {
+			errs = append(errs, err)
 		}
-		return err
 	}
-	for bindingKey, binding := range module.bindings {
-		if foundBinding, ok := injector.bindings[bindingKey]; ok {
-			return errAlreadyBound.withTag("bindingKey", bindingKey).withTag("foundBinding", foundBinding)
+	// bindings of the module for binding keys that were already bound in the module
+	for _, duplicate := range module.duplicateBindings {
+		if !injector.options.LenientOverrides {
+			errs = append(errs, duplicate.err)
+			continue
+		}
+		if err := replaceBinding(injector, module, duplicate.bindingKey, duplicate.binding, duplicate.declaringModule); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func installBinding(injector *injector, module *module, bindingKey bindingKey, binding binding, declaringModule *module, resolvedBindings map[binding]resolvedBinding) error {
+	resolvedBinding, ok := resolvedBindings[binding]
+	if !ok {
+		var err error
+		resolvedBinding, err = resolveBinding(injector, module, bindingKey, binding)
+		if err != nil {
+			return err
+		}
+		resolvedBindings[binding] = resolvedBinding
+	}
+	if foundBinding, ok := injector.bindings[bindingKey]; ok {
+		mergedBinding, err := mergeResolvedBindings(bindingKey, foundBinding, resolvedBinding)
+		if err != nil {
+			if injector.options.LenientOverrides && errors.Is(err, ErrAlreadyBound) {
+				injector.logf("inject: replacing binding %s of %s with %s", foundBinding, bindingKey, resolvedBinding)
+				injector.bindings[bindingKey] = resolvedBinding
+				injector.declaringModules[bindingKey] = declaringModule
+				return nil
+			}
+			return err
 		}
+		resolvedBinding = mergedBinding
+	} else if injector.parent != nil && bindingKey.reflectType() != injectorReflectType {
 		// check parent bindings, but allow replacing the binding of the injector
-		if injector.parent != nil && bindingKey.reflectType() != injectorReflectType {
-			if foundBinding, ok := injector.parent.bindings[bindingKey]; ok {
-				return errAlreadyBound.withTag("bindingKey", bindingKey).withTag("foundBinding", foundBinding).withTag("scope", "parent")
+		if foundBinding, err := injector.parent.getBinding(bindingKey); err == nil {
+			mergedBinding, err := mergeResolvedBindings(bindingKey, foundBinding, resolvedBinding)
+			if err != nil {
+				if injector.options.LenientOverrides && errors.Is(err, ErrAlreadyBound) {
+					injector.logf("inject: replacing binding %s of %s of the parent injector with %s", foundBinding, bindingKey, resolvedBinding)
+				} else {
+					return err.withTag("scope", "parent")
+				}
+			} else {
+				resolvedBinding = mergedBinding
 			}
 		}
-		resolvedBinding, err := binding.resolvedBinding(module, injector)
-		if err != nil {
+	}
+	injector.bindings[bindingKey] = resolvedBinding
+	if _, ok := injector.declaringModules[bindingKey]; !ok {
+		injector.declaringModules[bindingKey] = declaringModule
+	}
+	return nil
+}
+
+// replaceBinding replaces the binding of the injector for bindingKey with binding
+func replaceBinding(injector *injector, module *module, bindingKey bindingKey, binding binding, declaringModule *module) error {
+	resolvedBinding, err := resolveBinding(injector, module, bindingKey, binding)
+	if err != nil {
+		return err
+	}
+	injector.logf("inject: replacing binding %s of %s with %s", injector.bindings[bindingKey], bindingKey, resolvedBinding)
+	injector.bindings[bindingKey] = resolvedBinding
+	injector.declaringModules[bindingKey] = declaringModule
+	return nil
+}
+
+// resolveBinding returns the resolved binding of binding for the injector, adding
+// bindingKey to errors that are not about another binding
+func resolveBinding(injector *injector, module *module, bindingKey bindingKey, binding binding) (resolvedBinding, error) {
+	resolvedBinding, err := binding.resolvedBinding(module, injector)
+	if err != nil {
+		if injectErr, ok := err.(*Error); ok && injectErr.Key == (Key{}) {
+			return nil, injectErr.withBindingKey(bindingKey)
+		}
+		return nil, err
+	}
+	return resolvedBinding, nil
+}
+
+// validate returns the errors of all bindings of the injector, with the binding
+// key of the binding that requires a missing binding as the "requiredBy" tag.
+// If reachable is not nil, only the bindings in reachable are validated.
+func validate(injector *injector, reachable map[injectorBindingKey]bool) []error {
+	errs := validateBindings(injector, reachable)
+	if err := validateNoCycles(injector); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// validateBindings validates the bindings of the injector and its private injectors
+func validateBindings(injector *injector, reachable map[injectorBindingKey]bool) []error {
+	var errs []error
+	for _, bindingKey := range sortedBindingKeys(injector.bindings) {
+		if reachable != nil && !reachable[injectorBindingKey{injector, bindingKey}] {
+			continue
+		}
+		for _, err := range splitErrors(injector.bindings[bindingKey].validate()) {
+			if injectErr, ok := err.(*Error); ok {
+				err = injectErr.withTag("requiredBy", bindingKey)
+			}
+			errs = append(errs, err)
+		}
+	}
+	for _, privateInjector := range injector.privateInjectors {
+		errs = append(errs, validateBindings(privateInjector, reachable)...)
+	}
+	return errs
+}
+
+// validateNoCycles does a depth-first search of the dependency graph of the bindings
+// of the injector, returning an error with the full path of the first cycle found.
+// The search includes the bindings of private injectors, and follows exposed
+// bindings into private injectors and the bindings of private injectors back to
+// the injector.
+func validateNoCycles(injector *injector) error {
+	return findCycles(injector, make(map[injectorBindingKey]bool))
+}
+
+func findCycles(injector *injector, visited map[injectorBindingKey]bool) error {
+	for _, bindingKey := range sortedBindingKeys(injector.bindings) {
+		if err := findCycle(injectorBindingKey{injector, bindingKey}, visited, nil); err != nil {
+			return err
+		}
+	}
+	for _, privateInjector := range injector.privateInjectors {
+		if err := findCycles(privateInjector, visited); err != nil {
 			return err
 		}
-		injector.bindings[bindingKey] = resolvedBinding
 	}
 	return nil
 }
 
-func validate(injector *injector) error {
-	for _, resolvedBinding := range injector.bindings {
-		if err := resolvedBinding.validate(); err != nil {
+func findCycle(key injectorBindingKey, visited map[injectorBindingKey]bool, path []injectorBindingKey) error {
+	injector, binding := key.injector.findBinding(key.bindingKey)
+	if binding == nil {
+		return nil
+	}
+	key.injector = injector
+	if visited[key] {
+		return nil
+	}
+	for ii, pathKey := range path {
+		if pathKey == key {
+			var cycle []bindingKey
+			for _, cycleKey := range path[ii:] {
+				// an exposed binding and its private binding have the same binding key
+				if len(cycle) == 0 || cycle[len(cycle)-1] != cycleKey.bindingKey {
+					cycle = append(cycle, cycleKey.bindingKey)
+				}
+			}
+			cycle = append(cycle, key.bindingKey)
+			return ErrDependencyCycle.withTag("cycle", bindingKeyPathString(cycle))
+		}
+	}
+	path = append(path, key)
+	if exposedBinding, ok := binding.(*exposedBinding); ok {
+		if err := findCycle(injectorBindingKey{exposedBinding.privateInjector, key.bindingKey}, visited, path); err != nil {
 			return err
 		}
 	}
+	for _, dependency := range binding.dependencies() {
+		if dependency.lazy {
+			continue
+		}
+		if err := findCycle(injectorBindingKey{injector, dependency.bindingKey}, visited, path); err != nil {
+			return err
+		}
+	}
+	visited[key] = true
 	return nil
 }
 
@@ -121,11 +396,19 @@ func (i *injector) keyValueStrings() []string {
 }
 
 func (i *injector) Get(from interface{}) (interface{}, error) {
-	return i.get(newBindingKey(reflect.TypeOf(from)))
+	return i.GetContext(context.Background(), from)
+}
+
+func (i *injector) GetContext(ctx context.Context, from interface{}) (interface{}, error) {
+	return i.get(ctx, nil, newBindingKey(reflectTypeOf(from)))
 }
 
 func (i *injector) GetTagged(tag string, from interface{}) (interface{}, error) {
-	return i.get(newTaggedBindingKey(reflect.TypeOf(from), tag))
+	return i.GetTaggedContext(context.Background(), tag, from)
+}
+
+func (i *injector) GetTaggedContext(ctx context.Context, tag string, from interface{}) (interface{}, error) {
+	return i.get(ctx, nil, newTaggedBindingKey(reflectTypeOf(from), tag))
 }
 
 func (i *injector) GetTaggedBool(tag string) (bool, error) {
@@ -256,20 +539,103 @@ func (i *injector) GetTaggedString(tag string) (string, error) {
 	return obj.(string), nil
 }
 
+func (i *injector) GetTaggedDuration(tag string) (time.Duration, error) {
+	obj, err := i.getTaggedConstant(tag, durationConstantKind)
+	if err != nil {
+		return durationConstant, err
+	}
+	return obj.(time.Duration), nil
+}
+
+func (i *injector) GetTaggedTime(tag string) (time.Time, error) {
+	obj, err := i.getTaggedConstant(tag, timeConstantKind)
+	if err != nil {
+		return timeConstant, err
+	}
+	return obj.(time.Time), nil
+}
+
+func (i *injector) GetTaggedStrings(tag string) ([]string, error) {
+	obj, err := i.getTaggedConstant(tag, stringsConstantKind)
+	if err != nil {
+		return stringsConstant, err
+	}
+	return obj.([]string), nil
+}
+
+func (i *injector) GetTaggedURL(tag string) (*url.URL, error) {
+	obj, err := i.getTaggedConstant(tag, urlConstantKind)
+	if err != nil {
+		return urlConstant, err
+	}
+	return obj.(*url.URL), nil
+}
+
+func (i *injector) GetTaggedIP(tag string) (net.IP, error) {
+	obj, err := i.getTaggedConstant(tag, ipConstantKind)
+	if err != nil {
+		return ipConstant, err
+	}
+	return obj.(net.IP), nil
+}
+
+func (i *injector) GetTaggedConstant(tag string, from interface{}) (interface{}, error) {
+	fromReflectType := reflectTypeOf(from)
+	if fromReflectType == nil {
+		return nil, ErrNil
+	}
+	if !isSupportedBindConstantReflectType(fromReflectType) {
+		return nil, ErrNotSupportedBindType.withTag("reflectType", fromReflectType)
+	}
+	return i.get(context.Background(), nil, newTaggedBindingKey(fromReflectType, tag))
+}
+
 func (i *injector) getTaggedConstant(tag string, constantKind constantKind) (interface{}, error) {
-	return i.get(newTaggedBindingKey(constantKind.reflectType(), tag))
+	return i.get(context.Background(), nil, newTaggedBindingKey(constantKind.reflectType(), tag))
 }
 
 func (i *injector) Call(function interface{}) ([]interface{}, error) {
+	return i.CallContext(context.Background(), function)
+}
+
+func (i *injector) CallContext(ctx context.Context, function interface{}) ([]interface{}, error) {
 	funcReflectType := reflect.TypeOf(function)
 	if err := verifyIsFunc(funcReflectType); err != nil {
 		return nil, err
 	}
-	bindingKeys := getParameterBindingKeysForFunc(funcReflectType)
-	if err := i.validateBindingKeys(bindingKeys); err != nil {
+	dependencies := getParameterDependenciesForFunc(funcReflectType)
+	if err := i.validateDependencies(dependencies); err != nil {
+		return nil, err
+	}
+	reflectValues, err := i.getReflectValues(ctx, nil, dependencies)
+	if err != nil {
+		return nil, err
+	}
+	if isContextFunc(funcReflectType) {
+		reflectValues = prependContextReflectValue(ctx, reflectValues)
+	}
+	returnValues := reflect.ValueOf(function).Call(reflectValues)
+	return reflectValuesToValues(returnValues), nil
+}
+
+func (i *injector) CallWith(function interface{}, arguments ...interface{}) ([]interface{}, error) {
+	argumentReflectTypes := make([]reflect.Type, len(arguments))
+	argumentReflectValues := make([]reflect.Value, len(arguments))
+	for ii, argument := range arguments {
+		if argument == nil {
+			return nil, ErrNil.withTag("argument", ii)
+		}
+		argumentReflectTypes[ii] = reflect.TypeOf(argument)
+		argumentReflectValues[ii] = reflect.ValueOf(argument)
+	}
+	assistedCall, err := newAssistedCall(reflect.TypeOf(function), argumentReflectTypes)
+	if err != nil {
 		return nil, err
 	}
-	reflectValues, err := i.getReflectValues(bindingKeys)
+	if err := i.validateDependencies(assistedCall.dependencies); err != nil {
+		return nil, err
+	}
+	reflectValues, err := assistedCall.reflectValues(context.Background(), nil, i, argumentReflectValues)
 	if err != nil {
 		return nil, err
 	}
@@ -278,15 +644,19 @@ func (i *injector) Call(function interface{}) ([]interface{}, error) {
 }
 
 func (i *injector) CallTagged(taggedFunction interface{}) ([]interface{}, error) {
+	return i.CallTaggedContext(context.Background(), taggedFunction)
+}
+
+func (i *injector) CallTaggedContext(ctx context.Context, taggedFunction interface{}) ([]interface{}, error) {
 	taggedFuncReflectType := reflect.TypeOf(taggedFunction)
-	if err := verifyIsTaggedFunc(taggedFuncReflectType); err != nil {
+	if err := verifyIsTaggedFunc(taggedFuncReflectType, i.options.tagKey()); err != nil {
 		return nil, err
 	}
-	bindingKeys := getParameterBindingKeysForTaggedFunc(taggedFuncReflectType)
-	if err := i.validateBindingKeys(bindingKeys); err != nil {
+	dependencies := getParameterDependenciesForTaggedFunc(taggedFuncReflectType, i.options.tagKey())
+	if err := i.validateDependencies(dependencies); err != nil {
 		return nil, err
 	}
-	reflectValues, err := i.getReflectValues(bindingKeys)
+	reflectValues, err := i.getReflectValues(ctx, nil, dependencies)
 	if err != nil {
 		return nil, err
 	}
@@ -302,14 +672,14 @@ func (i *injector) Populate(populateStructPtr interface{}) error {
 		return err
 	}
 	populateStructValue := reflect.Indirect(reflect.ValueOf(populateStructPtr))
-	if err := verifyStructCanBePopulated(populateStructValue.Type()); err != nil {
+	if err := verifyStructCanBePopulated(populateStructValue.Type(), i.options.tagKey()); err != nil {
 		return err
 	}
-	bindingKeys := getStructFieldBindingKeys(populateStructValue.Type())
-	if err := i.validateBindingKeys(bindingKeys); err != nil {
+	dependencies := getStructFieldDependencies(populateStructValue.Type(), i.options.tagKey())
+	if err := i.validateDependencies(dependencies); err != nil {
 		return err
 	}
-	reflectValues, err := i.getReflectValues(bindingKeys)
+	reflectValues, err := i.getReflectValues(context.Background(), nil, dependencies)
 	if err != nil {
 		return err
 	}
@@ -318,63 +688,135 @@ func (i *injector) Populate(populateStructPtr interface{}) error {
 }
 
 func (i *injector) NewChildInjector(modules ...Module) (Injector, error) {
-	injector := &injector{i, make(map[bindingKey]resolvedBinding)}
-	_, err := initInjector(injector, modules)
+	injector := &injector{i, make(map[bindingKey]resolvedBinding), newConstructedSingletons(), make(map[bindingKey]*module), nil, i.options, nil}
+	_, err := initInjector(context.Background(), injector, modules)
 	if err != nil {
 		return nil, err
 	}
 	return injector, nil
 }
 
-func (i *injector) get(bindingKey bindingKey) (interface{}, error) {
+func (i *injector) Close(ctx context.Context) error {
+	return i.constructed.close(ctx)
+}
+
+func (i *injector) get(ctx context.Context, r *resolution, bindingKey bindingKey) (interface{}, error) {
 	binding, err := i.getBinding(bindingKey)
 	if err != nil {
-		return nil, err
+		return nil, newResolutionError(r.with(bindingKey, nil), err)
 	}
-	return binding.get()
+	return i.resolve(ctx, r, bindingKey, binding)
 }
 
 func (i *injector) getBinding(bindingKey bindingKey) (resolvedBinding, error) {
-	// get binding from parent, if any, but not the injector itself
-	if i.parent != nil && bindingKey.reflectType() != injectorReflectType {
-		binding, err := i.parent.getBinding(bindingKey)
-		if err == nil {
-			return binding, nil
+	// local bindings come first, they can only differ from the bindings of the
+	// parent for the injector itself, for multibindings that extend the parent's
+	// and for bindings replaced with Options.LenientOverrides
+	if _, binding := i.findBinding(bindingKey); binding != nil {
+		return binding, nil
+	}
+	for injector := i; injector != nil; injector = injector.parent {
+		if injector.hasPrivateBinding(bindingKey) {
+			return nil, ErrPrivateBinding.withBindingKey(bindingKey)
 		}
 	}
-	// get local binding
-	binding, ok := i.bindings[bindingKey]
-	if !ok {
-		return nil, errNoBinding.withTag("bindingKey", bindingKey)
+	return nil, ErrNoBinding.withBindingKey(bindingKey)
+}
+
+// findBinding returns the binding for bindingKey and the injector that has it,
+// which is the injector or one of its parents, or nil if not bound
+func (i *injector) findBinding(bindingKey bindingKey) (*injector, resolvedBinding) {
+	for injector := i; injector != nil; injector = injector.parent {
+		if binding, ok := injector.bindings[bindingKey]; ok {
+			return injector, binding
+		}
 	}
-	return binding, nil
+	return nil, nil
 }
 
-func (i *injector) getReflectValues(bindingKeys []bindingKey) ([]reflect.Value, error) {
-	numBindingKeys := len(bindingKeys)
-	reflectValues := make([]reflect.Value, numBindingKeys)
-	for ii := 0; ii < numBindingKeys; ii++ {
-		value, err := i.get(bindingKeys[ii])
+func (i *injector) getReflectValues(ctx context.Context, r *resolution, dependencies []dependency) ([]reflect.Value, error) {
+	reflectValues := make([]reflect.Value, len(dependencies))
+	for ii, dependency := range dependencies {
+		reflectValue, err := i.getReflectValue(ctx, r, dependency)
 		if err != nil {
 			return nil, err
 		}
-		reflectValues[ii] = reflect.ValueOf(value)
+		reflectValues[ii] = reflectValue
 	}
 	return reflectValues, nil
 }
 
-func (i *injector) validateBindingKeys(bindingKeys []bindingKey) error {
-	for _, bindingKey := range bindingKeys {
-		if _, err := i.getBinding(bindingKey); err != nil {
-			return err
+func (i *injector) getReflectValue(ctx context.Context, r *resolution, dependency dependency) (reflect.Value, error) {
+	binding, err := i.getBinding(dependency.bindingKey)
+	if err != nil {
+		if dependency.optional {
+			return reflect.Zero(dependency.reflectType), nil
 		}
+		return reflect.Value{}, newResolutionError(r.with(dependency.bindingKey, nil), err)
 	}
-	return nil
+	if dependency.provider {
+		return dependency.providerReflectValue(ctx, r, i, binding), nil
+	}
+	value, err := i.resolve(ctx, r, dependency.bindingKey, binding)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return dependency.reflectValue(value)
+}
+
+// validateDependencies returns an error for every dependency that is not bound,
+// skipping optional dependencies
+func (i *injector) validateDependencies(dependencies []dependency) error {
+	var errs []error
+	for _, dependency := range dependencies {
+		if dependency.optional {
+			continue
+		}
+		if _, err := i.getBinding(dependency.bindingKey); err != nil {
+			if errors.Is(err, ErrNoBinding) && i.installFailed(dependency.bindingKey) {
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return joinErrors(errs)
+}
+
+// installFailed returns true if the binding for bindingKey could not be
+// installed to the injector or one of its parents
+func (i *injector) installFailed(bindingKey bindingKey) bool {
+	key := newKey(bindingKey)
+	for injector := i; injector != nil; injector = injector.parent {
+		if injector.failedKeys[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// failedKeys returns the keys of the errors installing bindings
+func failedKeys(errs []error) map[Key]bool {
+	keys := make(map[Key]bool)
+	for _, err := range errs {
+		var injectErr *Error
+		if errors.As(err, &injectErr) && injectErr.Key.Type != nil {
+			keys[injectErr.Key] = true
+		}
+	}
+	return keys
+}
+
+func castInjector(i Injector) (*injector, error) {
+	castInjector, ok := i.(*injector)
+	if !ok {
+		return nil, ErrCannotCastInjector
+	}
+	return castInjector, nil
 }
 
 func verifyIsStructPtr(reflectType reflect.Type) error {
 	if !isStructPtr(reflectType) {
-		return errNotStructPtr.withTag("reflectType", reflectType)
+		return ErrNotStructPtr.withTag("reflectType", reflectType)
 	}
 	return nil
 }
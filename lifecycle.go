@@ -0,0 +1,119 @@
+package inject
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+)
+
+type constructedSingletons struct {
+	lock   sync.Mutex
+	values []interface{}
+}
+
+func newConstructedSingletons() *constructedSingletons {
+	return &constructedSingletons{}
+}
+
+func (c *constructedSingletons) add(value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.values = append(c.values, value)
+}
+
+// close stops the recorded singletons in reverse order, each singleton at most once
+func (c *constructedSingletons) close(ctx context.Context) error {
+	c.lock.Lock()
+	values := c.values
+	c.values = nil
+	c.lock.Unlock()
+	var errs []error
+	for i := len(values) - 1; i >= 0; i-- {
+		if err := stop(ctx, values[i]); err != nil {
+			errs = append(errs, ErrCloseErrors.withTag("singleton", reflect.TypeOf(values[i])).withCause(err))
+		}
+	}
+	return joinErrors(errs)
+}
+
+func stop(ctx context.Context, value interface{}) error {
+	switch value := value.(type) {
+	case Stopper:
+		return value.Stop(ctx)
+	case io.Closer:
+		return value.Close()
+	default:
+		return nil
+	}
+}
+
+// onConstructHook is a function bound with Builder.OnConstruct
+type onConstructHook struct {
+	fn reflect.Value
+	// whether the first parameter is a context.Context
+	context bool
+}
+
+func newOnConstructHook(bindingKeys []bindingKey, fn interface{}) (onConstructHook, error) {
+	fnReflectType := reflect.TypeOf(fn)
+	if !isFunc(fnReflectType) {
+		return onConstructHook{}, ErrNotFunction.withTag("funcReflectType", fnReflectType)
+	}
+	context := isContextFunc(fnReflectType)
+	numIn := 1
+	if context {
+		numIn = 2
+	}
+	if fnReflectType.NumIn() != numIn || fnReflectType.NumOut() != 1 || fnReflectType.Out(0) != errorReflectType {
+		return onConstructHook{}, ErrOnConstructInvalid.withTag("funcReflectType", fnReflectType)
+	}
+	valueReflectType := fnReflectType.In(numIn - 1)
+	for _, bindingKey := range bindingKeys {
+		if !elementReflectType(bindingKey.reflectType()).AssignableTo(valueReflectType) {
+			return onConstructHook{}, ErrNotAssignable.withBindingKey(bindingKey).withTag("funcReflectType", fnReflectType)
+		}
+	}
+	return onConstructHook{reflect.ValueOf(fn), context}, nil
+}
+
+func (o onConstructHook) call(ctx context.Context, value interface{}) error {
+	valueReflectValue := reflectValueOf(value, o.fn.Type().In(o.fn.Type().NumIn()-1))
+	reflectValues := []reflect.Value{valueReflectValue}
+	if o.context {
+		reflectValues = prependContextReflectValue(ctx, reflectValues)
+	}
+	if err := o.fn.Call(reflectValues)[0].Interface(); err != nil {
+		return err.(error)
+	}
+	return nil
+}
+
+// initialize calls Init if the value is an Initializer, then the hooks, returning
+// an ErrInitFailed with the key of the binding being resolved if any fails or panics
+func initialize(ctx context.Context, r *resolution, value interface{}, hooks []onConstructHook) (retErr error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			retErr = withPanic(newInitError(r, value, nil), recovered)
+		}
+	}()
+	if initializer, ok := value.(Initializer); ok {
+		if err := initializer.Init(ctx); err != nil {
+			return newInitError(r, value, err)
+		}
+	}
+	for _, hook := range hooks {
+		if err := hook.call(ctx, value); err != nil {
+			return newInitError(r, value, err).withTag("onConstruct", hook.fn.Type())
+		}
+	}
+	return nil
+}
+
+func newInitError(r *resolution, value interface{}, cause error) *Error {
+	err := ErrInitFailed.withTag("value", reflect.TypeOf(value)).withCause(cause)
+	if r != nil {
+		err = err.withBindingKey(r.bindingKey)
+	}
+	return err
+}
@@ -0,0 +1,233 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errStopTwo = errors.New("two failed")
+
+type stopRecorder struct {
+	stopped []string
+}
+
+type StoppableOne struct {
+	recorder *stopRecorder
+}
+
+func (s *StoppableOne) Stop(ctx context.Context) error {
+	s.recorder.stopped = append(s.recorder.stopped, "one")
+	return nil
+}
+
+type StoppableTwo struct {
+	recorder *stopRecorder
+	one      *StoppableOne
+}
+
+func (s *StoppableTwo) Stop(ctx context.Context) error {
+	s.recorder.stopped = append(s.recorder.stopped, "two")
+	return errStopTwo
+}
+
+type CloserThree struct {
+	recorder *stopRecorder
+}
+
+func (c *CloserThree) Close() error {
+	c.recorder.stopped = append(c.recorder.stopped, "three")
+	return nil
+}
+
+func newStoppableModule(recorder *stopRecorder) Module {
+	module := NewModule()
+	module.Bind(&stopRecorder{}).ToSingleton(recorder)
+	module.Bind(&StoppableOne{}).ToSingletonConstructor(func(r *stopRecorder) *StoppableOne { return &StoppableOne{r} })
+	module.Bind(&StoppableTwo{}).ToSingletonConstructor(func(r *stopRecorder, one *StoppableOne) *StoppableTwo { return &StoppableTwo{r, one} })
+	module.Bind(&CloserThree{}).ToSingletonConstructor(func(r *stopRecorder) *CloserThree { return &CloserThree{r} })
+	return module
+}
+
+func TestCloseReverseConstructionOrder(t *testing.T) {
+	recorder := &stopRecorder{}
+	injector, err := NewInjector(newStoppableModule(recorder))
+	require.NoError(t, err)
+	_, err = injector.Get(&StoppableTwo{})
+	require.NoError(t, err)
+	_, err = injector.Get(&CloserThree{})
+	require.NoError(t, err)
+
+	err = injector.Close(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeCloseErrors)
+	require.Contains(t, err.Error(), "two failed")
+	require.True(t, errors.Is(err, ErrCloseErrors))
+	require.True(t, errors.Is(err, errStopTwo))
+	require.Equal(t, []string{"three", "two", "one"}, recorder.stopped)
+
+	// singletons are only stopped once
+	require.NoError(t, injector.Close(context.Background()))
+	require.Equal(t, []string{"three", "two", "one"}, recorder.stopped)
+}
+
+func TestCloseOnlyConstructedSingletons(t *testing.T) {
+	recorder := &stopRecorder{}
+	injector, err := NewInjector(newStoppableModule(recorder))
+	require.NoError(t, err)
+	_, err = injector.Get(&CloserThree{})
+	require.NoError(t, err)
+	require.NoError(t, injector.Close(context.Background()))
+	require.Equal(t, []string{"three"}, recorder.stopped)
+}
+
+func TestCloseChildInjector(t *testing.T) {
+	recorder := &stopRecorder{}
+	parentModule := NewModule()
+	parentModule.Bind(&stopRecorder{}).ToSingleton(recorder)
+	parentModule.Bind(&StoppableOne{}).ToSingletonConstructor(func(r *stopRecorder) *StoppableOne { return &StoppableOne{r} })
+	parent, err := NewInjector(parentModule)
+	require.NoError(t, err)
+	_, err = parent.Get(&StoppableOne{})
+	require.NoError(t, err)
+
+	module := NewModule()
+	module.Bind(&CloserThree{}).ToSingletonConstructor(func(r *stopRecorder) *CloserThree { return &CloserThree{r} })
+	child, err := parent.NewChildInjector(module)
+	require.NoError(t, err)
+	_, err = child.Get(&CloserThree{})
+	require.NoError(t, err)
+
+	require.NoError(t, child.Close(context.Background()))
+	require.Equal(t, []string{"three"}, recorder.stopped)
+	require.NoError(t, parent.Close(context.Background()))
+	require.Equal(t, []string{"three", "one"}, recorder.stopped)
+}
+
+type initCounter struct {
+	inits int
+	err   error
+}
+
+func (i *initCounter) Init(ctx context.Context) error {
+	i.inits++
+	return i.err
+}
+
+func TestInitializer(t *testing.T) {
+	constructed := 0
+	newInitCounter := func() *initCounter {
+		constructed++
+		return &initCounter{}
+	}
+	module := NewModule()
+	module.Bind(&initCounter{}).ToSingletonConstructor(newInitCounter)
+	module.BindTagged("transient", &initCounter{}).ToConstructor(newInitCounter)
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		object, err := injector.Get(&initCounter{})
+		require.NoError(t, err)
+		require.Equal(t, 1, object.(*initCounter).inits)
+		object, err = injector.GetTagged("transient", &initCounter{})
+		require.NoError(t, err)
+		require.Equal(t, 1, object.(*initCounter).inits)
+	}
+	require.Equal(t, 3, constructed)
+}
+
+func TestInitializerFails(t *testing.T) {
+	initErr := errors.New("init failed")
+	module := NewModule()
+	module.Bind(&initCounter{}).ToSingletonConstructor(func() *initCounter { return &initCounter{err: initErr} })
+	module.Bind((*SimpleInterface)(nil)).ToConstructor(func(*initCounter) SimpleInterface { return &SimplePtrStruct{"hello"} })
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	_, err = injector.Get((*SimpleInterface)(nil))
+	require.True(t, errors.Is(err, ErrInitFailed))
+	require.True(t, errors.Is(err, initErr))
+	var injectErr *Error
+	require.True(t, errors.As(err, &injectErr))
+	require.Equal(t, Key{reflect.TypeOf(&initCounter{}), ""}, injectErr.Key)
+	var resolutionErr *ResolutionError
+	require.True(t, errors.As(err, &resolutionErr))
+	require.Len(t, resolutionErr.Path, 2)
+	// the failed singleton is not cached
+	_, err = injector.Get(&initCounter{})
+	require.True(t, errors.Is(err, ErrInitFailed))
+}
+
+func TestOnConstruct(t *testing.T) {
+	var calls []string
+	module := NewModule()
+	module.Bind(&initCounter{}).OnConstruct(func(i *initCounter) error {
+		calls = append(calls, "first")
+		require.Equal(t, 1, i.inits)
+		return nil
+	}).OnConstruct(func(ctx context.Context, i *initCounter) error {
+		calls = append(calls, "second")
+		require.NotNil(t, ctx)
+		return nil
+	}).ToSingletonConstructor(func() *initCounter { return &initCounter{} })
+	module.Bind((*SimpleInterface)(nil)).OnConstruct(func(s SimpleInterface) error {
+		return errors.New(s.Foo())
+	}).ToTaggedConstructor(func(struct{}) SimpleInterface { return &SimplePtrStruct{"hello"} })
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	_, err = injector.Get(&initCounter{})
+	require.NoError(t, err)
+	_, err = injector.Get(&initCounter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, calls)
+	_, err = injector.Get((*SimpleInterface)(nil))
+	require.True(t, errors.Is(err, ErrInitFailed))
+	require.Contains(t, err.Error(), "hello")
+}
+
+func TestOnConstructInvalid(t *testing.T) {
+	module := NewModule()
+	module.Bind(&initCounter{}).OnConstruct(func(*initCounter) {}).ToSingletonConstructor(func() *initCounter { return &initCounter{} })
+	module.Bind((*SimpleInterface)(nil)).OnConstruct(func(*SimplePtrStruct) error { return nil }).ToConstructor(createSimpleInterface)
+	module.Bind((*BarInterface)(nil)).OnConstruct(func(BarInterface) error { return nil }).ToSingleton(&BarPtrStruct{1})
+	module.BindTagged("notFunc", &initCounter{}).OnConstruct("hello").ToConstructor(func() *initCounter { return &initCounter{} })
+	_, err := NewInjector(module)
+	require.True(t, errors.Is(err, ErrOnConstructInvalid))
+	require.True(t, errors.Is(err, ErrNotAssignable))
+	require.True(t, errors.Is(err, ErrOnConstructNotSupported))
+	require.True(t, errors.Is(err, ErrNotFunction))
+}
+
+type panickingInit struct{}
+
+func (p *panickingInit) Init(ctx context.Context) error {
+	panic("oops")
+}
+
+func TestInitializerPanics(t *testing.T) {
+	module := NewModule()
+	module.Bind(&panickingInit{}).ToSingletonConstructor(func() *panickingInit { return &panickingInit{} })
+	module.Bind((*SimpleInterface)(nil)).OnConstruct(func(SimpleInterface) error {
+		panic(errXYZ)
+	}).ToConstructor(createSimpleInterface)
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	// the singleton is not left half constructed
+	for i := 0; i < 2; i++ {
+		_, err = injector.Get(&panickingInit{})
+		require.True(t, errors.Is(err, ErrInitFailed))
+		require.Contains(t, err.Error(), "panic:oops")
+	}
+	_, err = injector.Get((*SimpleInterface)(nil))
+	require.True(t, errors.Is(err, ErrInitFailed))
+	require.True(t, errors.Is(err, errXYZ))
+
+	// panics that escape the loaded function are errors of every load
+	l := newLoader(nil)
+	for i := 0; i < 2; i++ {
+		_, err = l.load(func() (interface{}, error) { panic("oops") })
+		require.True(t, errors.Is(err, ErrConstructorPanicked))
+	}
+}
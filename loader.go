@@ -8,21 +8,40 @@ import (
 type loader struct {
 	once  sync.Once
 	value atomic.Value
+	// called with the value once it was successfully loaded, can be nil
+	onLoad func(interface{})
 }
 
-func newLoader() *loader {
-	return &loader{sync.Once{}, atomic.Value{}}
+func newLoader(onLoad func(interface{})) *loader {
+	return &loader{sync.Once{}, atomic.Value{}, onLoad}
 }
 
 func (l *loader) load(f func() (interface{}, error)) (interface{}, error) {
 	l.once.Do(func() {
+		// stored in a defer so that a panic never leaves the loader without a value
+		var loaded *valueErr
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				loaded = &valueErr{nil, withPanic(ErrConstructorPanicked, recovered)}
+			}
+			l.value.Store(loaded)
+		}()
 		value, err := f()
-		l.value.Store(&valueErr{value, err})
+		if err == nil && l.onLoad != nil {
+			l.onLoad(value)
+		}
+		loaded = &valueErr{value, err}
 	})
 	valueErr := l.value.Load().(*valueErr)
 	return valueErr.value, valueErr.err
 }
 
+// loaded returns true if the value was successfully loaded
+func (l *loader) loaded() bool {
+	valueErr, ok := l.value.Load().(*valueErr)
+	return ok && valueErr.err == nil
+}
+
 type valueErr struct {
 	value interface{}
 	err   error
@@ -1,6 +1,7 @@
 package inject
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -10,10 +11,27 @@ type module struct {
 	bindings      map[bindingKey]binding
 	bindingErrors []error
 	eager         []*singletonBuilder
+	// the module every binding was declared in, which is another module for
+	// bindings of installed modules
+	declaringModules map[bindingKey]*module
+	// installed private modules, including those of installed modules
+	privateModules []*privateModule
+	// bindings for binding keys that were already bound, in declaration order
+	duplicateBindings []duplicateBinding
+}
+
+// duplicateBinding is a binding for a binding key that was already bound, which
+// is an error unless the injector has Options.LenientOverrides, in which case it
+// replaces the binding
+type duplicateBinding struct {
+	bindingKey      bindingKey
+	binding         binding
+	declaringModule *module
+	err             error
 }
 
 func newModule() *module {
-	return &module{bindings: make(map[bindingKey]binding), bindingErrors: make([]error, 0)}
+	return &module{bindings: make(map[bindingKey]binding), bindingErrors: make([]error, 0), declaringModules: make(map[bindingKey]*module)}
 }
 
 func (m *module) BindConstructor(fn interface{}) {
@@ -30,13 +48,9 @@ func (m *module) bindAuto(singleton bool, fn interface{}) SingletonBuilder {
 		m.addBindingError(err)
 		return nil
 	}
-	out := t.Out(0)
-	if out.Kind() == reflect.Interface {
-		out = reflect.PtrTo(out)
-	}
+	out := bindingKeyReflectTypeOf(t.Out(0))
 	if singleton {
-		m.Bind(out).ToSingletonConstructor(fn)
-		return newSingletonBuilder(m, out)
+		return m.Bind(out).ToSingletonConstructor(fn)
 	}
 	m.Bind(out).ToConstructor(fn)
 	return nil
@@ -140,30 +154,79 @@ func (m *module) BindTaggedString(tag string) Builder {
 	return m.bindTaggedConstant(tag, stringConstantKind)
 }
 
-func (m *module) bindTaggedConstant(tag string, constantKind constantKind) Builder {
+func (m *module) BindTaggedDuration(tag string) Builder {
+	return m.bindTaggedConstant(tag, durationConstantKind)
+}
+
+func (m *module) BindTaggedTime(tag string) Builder {
+	return m.bindTaggedConstant(tag, timeConstantKind)
+}
+
+func (m *module) BindTaggedStrings(tag string) Builder {
+	return m.bindTaggedConstant(tag, stringsConstantKind)
+}
+
+func (m *module) BindTaggedURL(tag string) Builder {
+	return m.bindTaggedConstant(tag, urlConstantKind)
+}
+
+func (m *module) BindTaggedIP(tag string) Builder {
+	return m.bindTaggedConstant(tag, ipConstantKind)
+}
+
+func (m *module) BindTaggedConstant(tag string, from interface{}) Builder {
 	if !m.verifyTag(tag) {
 		return newNoOpBuilder()
 	}
-	if !m.verifySupportedTypes([]interface{}{constantKind.constant()}, isSupportedBindConstantReflectType) {
+	if reflectTypeOf(from) == nil {
+		m.addBindingError(ErrNil)
 		return newNoOpBuilder()
 	}
-	return m.bind(func(fromReflectType reflect.Type) bindingKey { return newTaggedBindingKey(fromReflectType, tag) }, []interface{}{constantKind.constant()})
+	if !m.verifySupportedTypes([]interface{}{from}, isSupportedBindConstantReflectType) {
+		return newNoOpBuilder()
+	}
+	return m.bind(func(fromReflectType reflect.Type) bindingKey { return newTaggedBindingKey(fromReflectType, tag) }, []interface{}{from})
+}
+
+func (m *module) bindTaggedConstant(tag string, constantKind constantKind) Builder {
+	return m.BindTaggedConstant(tag, constantKind.constant())
+}
+
+func (m *module) BindSet(from interface{}) SetBuilder {
+	fromReflectType := reflectTypeOf(from)
+	if fromReflectType == nil {
+		m.addBindingError(ErrNil)
+		return newNoOpSetBuilder()
+	}
+	if !m.verifySupportedType(fromReflectType, isSupportedBindReflectType) {
+		return newNoOpSetBuilder()
+	}
+	return newSetBuilder(m, fromReflectType)
+}
+
+func (m *module) BindMap(from interface{}) MapBuilder {
+	fromReflectType := reflectTypeOf(from)
+	if fromReflectType == nil {
+		m.addBindingError(ErrNil)
+		return newNoOpMapBuilder()
+	}
+	if !m.verifySupportedType(fromReflectType, isSupportedBindReflectType) {
+		return newNoOpMapBuilder()
+	}
+	return newMapBuilder(m, fromReflectType)
 }
 
 func (m *module) bind(newBindingKeyFunc func(reflect.Type) bindingKey, from []interface{}) InterfaceBuilder {
 	lenFrom := len(from)
 	if lenFrom == 0 {
-		m.addBindingError(errNil)
+		m.addBindingError(ErrNil)
 		return newNoOpBuilder()
 	}
 	bindingKeys := make([]bindingKey, lenFrom)
 	for i := 0; i < lenFrom; i++ {
-		fromReflectType, ok := from[i].(reflect.Type)
-		if !ok {
-			fromReflectType = reflect.TypeOf(from[i])
-		}
+		fromReflectType := reflectTypeOf(from[i])
 		if fromReflectType == nil {
-			m.addBindingError(errNil)
+			m.addBindingError(ErrNil)
 			return newNoOpBuilder()
 		}
 		bindingKeys[i] = newBindingKeyFunc(fromReflectType)
@@ -177,15 +240,30 @@ func (m *module) String() string {
 
 func (m *module) Install(others ...Module) {
 	for _, mod := range others {
-		m.install(mod.(*module))
+		castModule, ok := castModule(mod)
+		if !ok {
+			m.addBindingError(ErrCannotCastModule)
+			continue
+		}
+		m.install(castModule)
 	}
 }
 func (m *module) install(o *module) {
 	m.bindingErrors = append(m.bindingErrors, o.bindingErrors...)
 	m.eager = append(m.eager, o.eager...)
-	for key, value := range o.bindings {
-		m.setBinding(key, value)
+	m.privateModules = append(m.privateModules, o.privateModules...)
+	for _, key := range sortedBindingKeys(o.bindings) {
+		m.setDeclaredBinding(key, o.bindings[key], o.declaringModules[key])
 	}
+	m.duplicateBindings = append(m.duplicateBindings, o.duplicateBindings...)
+}
+
+func (m *module) AddError(err error) {
+	if err == nil {
+		m.addBindingError(ErrNil)
+		return
+	}
+	m.addBindingError(err)
 }
 
 func (m *module) keyValueStrings() []string {
@@ -208,17 +286,33 @@ func (m *module) binding(bindingKey bindingKey) (binding, bool) {
 }
 
 func (m *module) setBinding(bindingKey bindingKey, binding binding) {
+	m.setDeclaredBinding(bindingKey, binding, m)
+}
+
+// setDeclaredBinding keeps the first declaring module of multibindings
+func (m *module) setDeclaredBinding(bindingKey bindingKey, binding binding, declaringModule *module) {
 	foundBinding, ok := m.bindings[bindingKey]
 	if ok {
-		m.addBindingError(errAlreadyBound.withTag("bindingKey", bindingKey).withTag("foundBinding", foundBinding))
-		return
+		mergedBinding, err := mergeBindings(bindingKey, foundBinding, binding)
+		if err != nil {
+			if errors.Is(err, ErrAlreadyBound) {
+				m.duplicateBindings = append(m.duplicateBindings, duplicateBinding{bindingKey, binding, declaringModule, err})
+				return
+			}
+			m.addBindingError(err)
+			return
+		}
+		binding = mergedBinding
 	}
 	m.bindings[bindingKey] = binding
+	if _, ok := m.declaringModules[bindingKey]; !ok {
+		m.declaringModules[bindingKey] = declaringModule
+	}
 }
 
 func (m *module) verifyTag(tag string) bool {
 	if tag == "" {
-		m.addBindingError(errTagEmpty)
+		m.addBindingError(ErrTagEmpty)
 		return false
 	}
 	return true
@@ -228,11 +322,7 @@ func (m *module) verifySupportedTypes(froms []interface{}, isSupportedFunc func(
 	ok := true
 	for _, from := range froms {
 		// adds an error, so want to loop all the way through
-		fromReflectType, ok := from.(reflect.Type)
-		if !ok {
-			fromReflectType = reflect.TypeOf(from)
-		}
-		if !m.verifySupportedType(fromReflectType, isSupportedFunc) {
+		if !m.verifySupportedType(reflectTypeOf(from), isSupportedFunc) {
 			ok = false
 		}
 	}
@@ -248,5 +338,25 @@ func (m *module) verifySupportedType(reflectType reflect.Type, isSupportedFunc f
 }
 
 func (m *module) addNotSupportedBindTypeError(reflectType reflect.Type) {
-	m.addBindingError(errNotSupportedBindType.withTag("reflectType", reflectType))
+	m.addBindingError(ErrNotSupportedBindType.withTag("reflectType", reflectType))
+}
+
+// castModule returns the module of m, which for private modules is a module that
+// only installs the private module, and for other Module implementations is a
+// module with the bindings declared by Configure
+func castModule(m Module) (*module, bool) {
+	switch m := m.(type) {
+	case *module:
+		return m, true
+	case *privateModule:
+		castModule := newModule()
+		castModule.privateModules = append(castModule.privateModules, m)
+		return castModule, true
+	case Configurer:
+		castModule := newModule()
+		m.Configure(castModule)
+		return castModule, true
+	default:
+		return nil, false
+	}
 }
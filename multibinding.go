@@ -0,0 +1,469 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type noOpSetBuilder struct{}
+
+func newNoOpSetBuilder() SetBuilder {
+	return &noOpSetBuilder{}
+}
+
+func (n *noOpSetBuilder) AddSingleton(singleton interface{}) {}
+
+func (n *noOpSetBuilder) AddConstructor(constructor interface{}) {}
+
+func (n *noOpSetBuilder) AddSingletonConstructor(constructor interface{}) {}
+
+func (n *noOpSetBuilder) AddTaggedConstructor(constructor interface{}) {}
+
+func (n *noOpSetBuilder) AddTaggedSingletonConstructor(constructor interface{}) {}
+
+type noOpMapBuilder struct{}
+
+func newNoOpMapBuilder() MapBuilder {
+	return &noOpMapBuilder{}
+}
+
+func (n *noOpMapBuilder) AddSingleton(key string, singleton interface{}) {}
+
+func (n *noOpMapBuilder) AddConstructor(key string, constructor interface{}) {}
+
+func (n *noOpMapBuilder) AddSingletonConstructor(key string, constructor interface{}) {}
+
+func (n *noOpMapBuilder) AddTaggedConstructor(key string, constructor interface{}) {}
+
+func (n *noOpMapBuilder) AddTaggedSingletonConstructor(key string, constructor interface{}) {}
+
+type setBuilder struct {
+	module *module
+	// the reflect.Type elements are verified against, a pointer for interfaces
+	elementKeyReflectType reflect.Type
+	bindingKey            bindingKey
+}
+
+func newSetBuilder(module *module, elementKeyReflectType reflect.Type) SetBuilder {
+	sliceReflectType := reflect.SliceOf(elementReflectType(elementKeyReflectType))
+	bindingKey := newBindingKey(sliceReflectType)
+	// an empty set is bound even if no elements are added
+	module.setBinding(bindingKey, newSetBinding(sliceReflectType))
+	return &setBuilder{module, elementKeyReflectType, bindingKey}
+}
+
+func (s *setBuilder) AddSingleton(singleton interface{}) {
+	s.add(singleton, verifyBindingReflectType, newSingletonBinding)
+}
+
+func (s *setBuilder) AddConstructor(constructor interface{}) {
+	s.add(constructor, verifyConstructorReflectType, newConstructorBinding)
+}
+
+func (s *setBuilder) AddSingletonConstructor(constructor interface{}) {
+	s.add(constructor, verifyConstructorReflectType, newSingletonConstructorBinding)
+}
+
+func (s *setBuilder) AddTaggedConstructor(constructor interface{}) {
+	s.add(constructor, verifyTaggedConstructorReflectType, newTaggedConstructorBinding)
+}
+
+func (s *setBuilder) AddTaggedSingletonConstructor(constructor interface{}) {
+	s.add(constructor, verifyTaggedConstructorReflectType, newTaggedSingletonConstructorBinding)
+}
+
+func (s *setBuilder) add(object interface{}, verifyFunc func(reflect.Type, reflect.Type) error, newBindingFunc func(interface{}) binding) {
+	if err := verifyFunc(s.elementKeyReflectType, reflect.TypeOf(object)); err != nil {
+		s.module.addBindingError(err)
+		return
+	}
+	s.module.setBinding(s.bindingKey, newSetBinding(s.bindingKey.reflectType(), newBindingFunc(object)))
+}
+
+// setBinding is a multibinding of a slice, with one binding per element
+type setBinding struct {
+	sliceReflectType reflect.Type
+	elements         []binding
+}
+
+func newSetBinding(sliceReflectType reflect.Type, elements ...binding) *setBinding {
+	return &setBinding{sliceReflectType, elements}
+}
+
+func (s *setBinding) String() string {
+	strs := make([]string, len(s.elements))
+	for i, element := range s.elements {
+		strs[i] = element.String()
+	}
+	return fmt.Sprintf("set{%s}", strings.Join(strs, " "))
+}
+
+func (s *setBinding) kind() Kind {
+	return SetKind
+}
+
+func (s *setBinding) dependencies() []dependency {
+	var dependencies []dependency
+	for _, element := range s.elements {
+		dependencies = append(dependencies, element.dependencies()...)
+	}
+	return dependencies
+}
+
+func (s *setBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
+	elements := make([]resolvedBinding, len(s.elements))
+	for i, element := range s.elements {
+		resolvedElement, err := element.resolvedBinding(module, injector)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = resolvedElement
+	}
+	return &resolvedSetBinding{s.sliceReflectType, s.elements, elements}, nil
+}
+
+type resolvedSetBinding struct {
+	sliceReflectType reflect.Type
+	// the bindings the elements were resolved from, to merge each at most once
+	sources  []binding
+	elements []resolvedBinding
+}
+
+func (r *resolvedSetBinding) String() string {
+	strs := make([]string, len(r.elements))
+	for i, element := range r.elements {
+		strs[i] = element.String()
+	}
+	return fmt.Sprintf("set{%s}", strings.Join(strs, " "))
+}
+
+func (r *resolvedSetBinding) validate() error {
+	var errs []error
+	for _, element := range r.elements {
+		errs = append(errs, splitErrors(element.validate())...)
+	}
+	return joinErrors(errs)
+}
+
+func (r *resolvedSetBinding) get(ctx context.Context, res *resolution) (interface{}, error) {
+	sliceReflectValue := reflect.MakeSlice(r.sliceReflectType, 0, len(r.elements))
+	for _, element := range r.elements {
+		value, err := element.get(ctx, res)
+		if err != nil {
+			return nil, err
+		}
+		sliceReflectValue = reflect.Append(sliceReflectValue, reflectValueOf(value, r.sliceReflectType.Elem()))
+	}
+	return sliceReflectValue.Interface(), nil
+}
+
+// constructed returns true if all elements were constructed
+func (r *resolvedSetBinding) constructed() bool {
+	return allConstructed(r.elements)
+}
+
+func (r *resolvedSetBinding) kind() Kind {
+	return SetKind
+}
+
+func (r *resolvedSetBinding) dependencies() []dependency {
+	var dependencies []dependency
+	for _, element := range r.elements {
+		dependencies = append(dependencies, element.dependencies()...)
+	}
+	return dependencies
+}
+
+type mapBuilder struct {
+	module *module
+	// the reflect.Type entries are verified against, a pointer for interfaces
+	elementKeyReflectType reflect.Type
+	bindingKey            bindingKey
+}
+
+func newMapBuilder(module *module, elementKeyReflectType reflect.Type) MapBuilder {
+	mapReflectType := reflect.MapOf(stringReflectType, elementReflectType(elementKeyReflectType))
+	bindingKey := newBindingKey(mapReflectType)
+	// an empty map is bound even if no entries are added
+	module.setBinding(bindingKey, newMapBinding(mapReflectType, nil, nil))
+	return &mapBuilder{module, elementKeyReflectType, bindingKey}
+}
+
+func (m *mapBuilder) AddSingleton(key string, singleton interface{}) {
+	m.add(key, singleton, verifyBindingReflectType, newSingletonBinding)
+}
+
+func (m *mapBuilder) AddConstructor(key string, constructor interface{}) {
+	m.add(key, constructor, verifyConstructorReflectType, newConstructorBinding)
+}
+
+func (m *mapBuilder) AddSingletonConstructor(key string, constructor interface{}) {
+	m.add(key, constructor, verifyConstructorReflectType, newSingletonConstructorBinding)
+}
+
+func (m *mapBuilder) AddTaggedConstructor(key string, constructor interface{}) {
+	m.add(key, constructor, verifyTaggedConstructorReflectType, newTaggedConstructorBinding)
+}
+
+func (m *mapBuilder) AddTaggedSingletonConstructor(key string, constructor interface{}) {
+	m.add(key, constructor, verifyTaggedConstructorReflectType, newTaggedSingletonConstructorBinding)
+}
+
+func (m *mapBuilder) add(key string, object interface{}, verifyFunc func(reflect.Type, reflect.Type) error, newBindingFunc func(interface{}) binding) {
+	if err := verifyFunc(m.elementKeyReflectType, reflect.TypeOf(object)); err != nil {
+		m.module.addBindingError(err)
+		return
+	}
+	m.module.setBinding(m.bindingKey, newMapBinding(m.bindingKey.reflectType(), []string{key}, []binding{newBindingFunc(object)}))
+}
+
+// mapBinding is a multibinding of a map, with one binding per entry
+type mapBinding struct {
+	mapReflectType reflect.Type
+	keys           []string
+	elements       []binding
+}
+
+func newMapBinding(mapReflectType reflect.Type, keys []string, elements []binding) *mapBinding {
+	return &mapBinding{mapReflectType, keys, elements}
+}
+
+func (m *mapBinding) String() string {
+	strs := make([]string, len(m.elements))
+	for i, element := range m.elements {
+		strs[i] = fmt.Sprintf("%s:%s", m.keys[i], element.String())
+	}
+	return fmt.Sprintf("map{%s}", strings.Join(strs, " "))
+}
+
+func (m *mapBinding) kind() Kind {
+	return MapKind
+}
+
+func (m *mapBinding) dependencies() []dependency {
+	var dependencies []dependency
+	for _, element := range m.elements {
+		dependencies = append(dependencies, element.dependencies()...)
+	}
+	return dependencies
+}
+
+func (m *mapBinding) resolvedBinding(module *module, injector *injector) (resolvedBinding, error) {
+	elements := make([]resolvedBinding, len(m.elements))
+	for i, element := range m.elements {
+		resolvedElement, err := element.resolvedBinding(module, injector)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = resolvedElement
+	}
+	return &resolvedMapBinding{m.mapReflectType, m.keys, m.elements, elements}, nil
+}
+
+type resolvedMapBinding struct {
+	mapReflectType reflect.Type
+	keys           []string
+	// the bindings the entries were resolved from, to merge each at most once
+	sources  []binding
+	elements []resolvedBinding
+}
+
+func (r *resolvedMapBinding) String() string {
+	strs := make([]string, len(r.elements))
+	for i, element := range r.elements {
+		strs[i] = fmt.Sprintf("%s:%s", r.keys[i], element.String())
+	}
+	return fmt.Sprintf("map{%s}", strings.Join(strs, " "))
+}
+
+func (r *resolvedMapBinding) validate() error {
+	var errs []error
+	for _, element := range r.elements {
+		errs = append(errs, splitErrors(element.validate())...)
+	}
+	return joinErrors(errs)
+}
+
+func (r *resolvedMapBinding) get(ctx context.Context, res *resolution) (interface{}, error) {
+	mapReflectValue := reflect.MakeMapWithSize(r.mapReflectType, len(r.elements))
+	for i, element := range r.elements {
+		value, err := element.get(ctx, res)
+		if err != nil {
+			return nil, err
+		}
+		mapReflectValue.SetMapIndex(reflect.ValueOf(r.keys[i]), reflectValueOf(value, r.mapReflectType.Elem()))
+	}
+	return mapReflectValue.Interface(), nil
+}
+
+// constructed returns true if all entries were constructed
+func (r *resolvedMapBinding) constructed() bool {
+	return allConstructed(r.elements)
+}
+
+func (r *resolvedMapBinding) kind() Kind {
+	return MapKind
+}
+
+func (r *resolvedMapBinding) dependencies() []dependency {
+	var dependencies []dependency
+	for _, element := range r.elements {
+		dependencies = append(dependencies, element.dependencies()...)
+	}
+	return dependencies
+}
+
+// mergeBindings combines two multibindings for the same binding key. Elements
+// and entries of second that are already in first, because a module was
+// installed more than once, are only kept once.
+func mergeBindings(bindingKey bindingKey, first binding, second binding) (binding, *Error) {
+	switch first := first.(type) {
+	case *setBinding:
+		if second, ok := second.(*setBinding); ok {
+			indexes := newElementIndexes(first.elements, second.elements)
+			return newSetBinding(first.sliceReflectType, appendBindings(first.elements, selectBindings(second.elements, indexes))...), nil
+		}
+	case *mapBinding:
+		if second, ok := second.(*mapBinding); ok {
+			indexes := newElementIndexes(first.elements, second.elements)
+			keys := selectKeys(second.keys, indexes)
+			if err := verifyNoDuplicateMapKeys(bindingKey, first.keys, keys); err != nil {
+				return nil, err
+			}
+			return newMapBinding(first.mapReflectType, appendKeys(first.keys, keys), appendBindings(first.elements, selectBindings(second.elements, indexes))), nil
+		}
+	}
+	return nil, ErrAlreadyBound.withBindingKey(bindingKey).withTag("foundBinding", first)
+}
+
+// overrideBindings combines two map bindings for the same binding key, where
+// entries of second replace entries of first with the same map key. Set
+// bindings are not combined, the set of second replaces the set of first.
+func overrideBindings(first binding, second binding) (binding, bool) {
+	switch first := first.(type) {
+	case *mapBinding:
+		if second, ok := second.(*mapBinding); ok {
+			overridden := make(map[string]bool, len(second.keys))
+			for _, key := range second.keys {
+				overridden[key] = true
+			}
+			var keys []string
+			var elements []binding
+			for i, key := range first.keys {
+				if !overridden[key] {
+					keys = append(keys, key)
+					elements = append(elements, first.elements[i])
+				}
+			}
+			return newMapBinding(first.mapReflectType, appendKeys(keys, second.keys), appendBindings(elements, second.elements)), true
+		}
+	}
+	return nil, false
+}
+
+// mergeResolvedBindings is the equivalent of mergeBindings for resolved bindings
+func mergeResolvedBindings(bindingKey bindingKey, first resolvedBinding, second resolvedBinding) (resolvedBinding, *Error) {
+	switch first := first.(type) {
+	case *resolvedSetBinding:
+		if second, ok := second.(*resolvedSetBinding); ok {
+			indexes := newElementIndexes(first.sources, second.sources)
+			return &resolvedSetBinding{
+				first.sliceReflectType,
+				appendBindings(first.sources, selectBindings(second.sources, indexes)),
+				appendResolvedBindings(first.elements, selectResolvedBindings(second.elements, indexes)),
+			}, nil
+		}
+	case *resolvedMapBinding:
+		if second, ok := second.(*resolvedMapBinding); ok {
+			indexes := newElementIndexes(first.sources, second.sources)
+			keys := selectKeys(second.keys, indexes)
+			if err := verifyNoDuplicateMapKeys(bindingKey, first.keys, keys); err != nil {
+				return nil, err
+			}
+			return &resolvedMapBinding{
+				first.mapReflectType,
+				appendKeys(first.keys, keys),
+				appendBindings(first.sources, selectBindings(second.sources, indexes)),
+				appendResolvedBindings(first.elements, selectResolvedBindings(second.elements, indexes)),
+			}, nil
+		}
+	}
+	return nil, ErrAlreadyBound.withBindingKey(bindingKey).withTag("foundBinding", first)
+}
+
+func verifyNoDuplicateMapKeys(bindingKey bindingKey, firstKeys []string, secondKeys []string) *Error {
+	found := make(map[string]bool, len(firstKeys))
+	for _, key := range firstKeys {
+		found[key] = true
+	}
+	for _, key := range secondKeys {
+		if found[key] {
+			return ErrDuplicateMapKey.withBindingKey(bindingKey).withTag("key", key)
+		}
+	}
+	return nil
+}
+
+func allConstructed(elements []resolvedBinding) bool {
+	for _, element := range elements {
+		if !element.constructed() {
+			return false
+		}
+	}
+	return true
+}
+
+// newElementIndexes returns the indexes of the elements of second that are not
+// in first
+func newElementIndexes(first []binding, second []binding) []int {
+	found := make(map[binding]bool, len(first))
+	for _, element := range first {
+		found[element] = true
+	}
+	indexes := make([]int, 0, len(second))
+	for i, element := range second {
+		if !found[element] {
+			found[element] = true
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func selectBindings(elements []binding, indexes []int) []binding {
+	selected := make([]binding, len(indexes))
+	for i, index := range indexes {
+		selected[i] = elements[index]
+	}
+	return selected
+}
+
+func selectResolvedBindings(elements []resolvedBinding, indexes []int) []resolvedBinding {
+	selected := make([]resolvedBinding, len(indexes))
+	for i, index := range indexes {
+		selected[i] = elements[index]
+	}
+	return selected
+}
+
+func selectKeys(keys []string, indexes []int) []string {
+	selected := make([]string, len(indexes))
+	for i, index := range indexes {
+		selected[i] = keys[index]
+	}
+	return selected
+}
+
+func appendBindings(first []binding, second []binding) []binding {
+	return append(append([]binding{}, first...), second...)
+}
+
+func appendResolvedBindings(first []resolvedBinding, second []resolvedBinding) []resolvedBinding {
+	return append(append([]resolvedBinding{}, first...), second...)
+}
+
+func appendKeys(first []string, second []string) []string {
+	return append(append([]string{}, first...), second...)
+}
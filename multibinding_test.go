@@ -0,0 +1,260 @@
+package inject
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func simpleInterfaceFoos(simpleInterfaces []SimpleInterface) []string {
+	foos := make([]string, len(simpleInterfaces))
+	for i, simpleInterface := range simpleInterfaces {
+		foos[i] = simpleInterface.Foo()
+	}
+	return foos
+}
+
+type PopulateSet struct {
+	SimpleInterfaces []SimpleInterface
+}
+
+func TestSetBinding(t *testing.T) {
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	module.BindSet((*SimpleInterface)(nil)).AddSingleton(&SimplePtrStruct{"one"})
+	module.BindSet((*SimpleInterface)(nil)).AddConstructor(func(b BarInterface) (SimpleInterface, error) {
+		return &SimplePtrStruct{"two"}, nil
+	})
+	module.BindSet((*SimpleInterface)(nil)).AddSingletonConstructor(createSimplePtrInterface)
+	module.BindSet((*SimpleInterface)(nil)).AddTaggedConstructor(func(s struct{ B BarInterface }) SimpleInterface {
+		return &SimplePtrStruct{"four"}
+	})
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			object, err := injector.Get([]SimpleInterface{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"one", "two", "default", "four"}, simpleInterfaceFoos(object.([]SimpleInterface)))
+
+			values, err := injector.Call(simpleInterfaceFoos)
+			require.NoError(t, err)
+			require.Equal(t, []string{"one", "two", "default", "four"}, values[0])
+
+			populateSet := &PopulateSet{}
+			require.NoError(t, injector.Populate(populateSet))
+			require.Len(t, populateSet.SimpleInterfaces, 4)
+		})
+	}
+}
+
+func TestSetBindingEmpty(t *testing.T) {
+	module := NewModule()
+	module.BindSet((*SimpleInterface)(nil))
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	object, err := injector.Get([]SimpleInterface{})
+	require.NoError(t, err)
+	require.Equal(t, []SimpleInterface{}, object)
+}
+
+func TestSetBindingStructPtr(t *testing.T) {
+	module := NewModule()
+	module.BindSet(&SimplePtrStruct{}).AddSingleton(&SimplePtrStruct{"one"})
+	module.BindSet(&SimplePtrStruct{}).AddSingleton(SimplePtrStruct{"two"})
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeNotAssignable)
+}
+
+func newSetModule(foos ...string) Module {
+	module := NewModule()
+	for _, foo := range foos {
+		module.BindSet((*SimpleInterface)(nil)).AddSingleton(&SimplePtrStruct{foo})
+	}
+	return module
+}
+
+func TestSetBindingAcrossModules(t *testing.T) {
+	installed := newSetModule("one")
+	installed.Install(newSetModule("two", "three"))
+	for _, injector := range createInjectors(t, installed) {
+		t.Run("install_"+injector.name, func(t *testing.T) {
+			object, err := injector.Get([]SimpleInterface{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"one", "two", "three"}, simpleInterfaceFoos(object.([]SimpleInterface)))
+		})
+	}
+
+	overridden := Override(newSetModule("one")).With(newSetModule("two"))
+	for _, injector := range createInjectors(t, overridden) {
+		t.Run("override_"+injector.name, func(t *testing.T) {
+			object, err := injector.Get([]SimpleInterface{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"two"}, simpleInterfaceFoos(object.([]SimpleInterface)))
+		})
+	}
+
+	injector, err := NewInjector(newSetModule("one"), NewModule(), newSetModule("two"))
+	require.NoError(t, err)
+	object, err := injector.Get([]SimpleInterface{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "two"}, simpleInterfaceFoos(object.([]SimpleInterface)))
+}
+
+func TestSetBindingInstalledTwice(t *testing.T) {
+	shared := newSetModule("shared")
+	first := newSetModule("one")
+	first.Install(shared)
+	second := newSetModule("two")
+	second.Install(shared)
+	installed := NewModule()
+	installed.Install(first, second)
+	for _, module := range []Module{installed, Override(installed).With(NewModule())} {
+		injector, err := NewInjector(module)
+		require.NoError(t, err)
+		object, err := injector.Get([]SimpleInterface{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"one", "shared", "two"}, simpleInterfaceFoos(object.([]SimpleInterface)))
+	}
+
+	injector, err := NewInjector(first, second)
+	require.NoError(t, err)
+	object, err := injector.Get([]SimpleInterface{})
+	require.NoError(t, err)
+	simpleInterfaces := object.([]SimpleInterface)
+	require.Equal(t, []string{"one", "shared", "two"}, simpleInterfaceFoos(simpleInterfaces))
+
+	child, err := injector.NewChildInjector(shared)
+	require.NoError(t, err)
+	object, err = child.Get([]SimpleInterface{})
+	require.NoError(t, err)
+	require.Equal(t, simpleInterfaces, object)
+}
+
+func TestSetBindingChildInjector(t *testing.T) {
+	parent, err := NewInjector(newSetModule("one"))
+	require.NoError(t, err)
+	child, err := parent.NewChildInjector(newSetModule("two"))
+	require.NoError(t, err)
+	grandChild, err := child.NewChildInjector(newSetModule("three"))
+	require.NoError(t, err)
+
+	object, err := parent.Get([]SimpleInterface{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one"}, simpleInterfaceFoos(object.([]SimpleInterface)))
+	object, err = child.Get([]SimpleInterface{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "two"}, simpleInterfaceFoos(object.([]SimpleInterface)))
+	object, err = grandChild.Get([]SimpleInterface{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "two", "three"}, simpleInterfaceFoos(object.([]SimpleInterface)))
+}
+
+func TestSetBindingConflictsWithBinding(t *testing.T) {
+	module := newSetModule("one")
+	module.Bind([]SimpleInterface{}).ToSingleton([]SimpleInterface{})
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeAlreadyBound)
+}
+
+func newMapModule(entries ...string) Module {
+	module := NewModule()
+	for _, entry := range entries {
+		module.BindMap((*SimpleInterface)(nil)).AddSingleton(entry, &SimplePtrStruct{entry})
+	}
+	return module
+}
+
+func simpleInterfaceMapFoos(simpleInterfaces map[string]SimpleInterface) map[string]string {
+	foos := make(map[string]string, len(simpleInterfaces))
+	for key, simpleInterface := range simpleInterfaces {
+		foos[key] = simpleInterface.Foo()
+	}
+	return foos
+}
+
+func TestMapBinding(t *testing.T) {
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	simpleInterfaces := module.BindMap((*SimpleInterface)(nil))
+	simpleInterfaces.AddSingleton("one", &SimplePtrStruct{"one"})
+	simpleInterfaces.AddConstructor("two", func(b BarInterface) (SimpleInterface, error) {
+		return &SimplePtrStruct{"two"}, nil
+	})
+	simpleInterfaces.AddSingletonConstructor("three", createSimplePtrInterface)
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			object, err := injector.Get(map[string]SimpleInterface{})
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"one": "one", "two": "two", "three": "default"}, simpleInterfaceMapFoos(object.(map[string]SimpleInterface)))
+
+			values, err := injector.CallTagged(func(s struct{ SimpleInterfaces map[string]SimpleInterface }) int {
+				return len(s.SimpleInterfaces)
+			})
+			require.NoError(t, err)
+			require.Equal(t, 3, values[0])
+		})
+	}
+}
+
+func TestMapBindingAcrossModules(t *testing.T) {
+	installed := newMapModule("one")
+	installed.Install(newMapModule("two"))
+	injector, err := NewInjector(installed, newMapModule("three"))
+	require.NoError(t, err)
+	child, err := injector.NewChildInjector(newMapModule("four"))
+	require.NoError(t, err)
+	object, err := child.Get(map[string]SimpleInterface{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"one": "one", "two": "two", "three": "three", "four": "four"}, simpleInterfaceMapFoos(object.(map[string]SimpleInterface)))
+	object, err = injector.Get(map[string]SimpleInterface{})
+	require.NoError(t, err)
+	require.Len(t, object, 3)
+}
+
+func TestMapBindingOverride(t *testing.T) {
+	override := NewModule()
+	override.BindMap((*SimpleInterface)(nil)).AddSingleton("one", &SimplePtrStruct{"overridden"})
+	injector, err := NewInjector(Override(newMapModule("one", "two")).With(override))
+	require.NoError(t, err)
+	object, err := injector.Get(map[string]SimpleInterface{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"one": "overridden", "two": "two"}, simpleInterfaceMapFoos(object.(map[string]SimpleInterface)))
+}
+
+func TestMapBindingInstalledTwice(t *testing.T) {
+	shared := newMapModule("shared")
+	first := newMapModule("one")
+	first.Install(shared)
+	second := newMapModule("two")
+	second.Install(shared)
+	injector, err := NewInjector(first, second)
+	require.NoError(t, err)
+	object, err := injector.Get(map[string]SimpleInterface{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"one": "one", "two": "two", "shared": "shared"}, simpleInterfaceMapFoos(object.(map[string]SimpleInterface)))
+}
+
+func TestMapBindingDuplicateKeys(t *testing.T) {
+	_, err := NewInjector(newMapModule("one", "one"))
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrDuplicateMapKey))
+
+	installed := newMapModule("one")
+	installed.Install(newMapModule("one"))
+	_, err = NewInjector(installed)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeDuplicateMapKey)
+
+	_, err = NewInjector(newMapModule("one"), newMapModule("two", "one"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeDuplicateMapKey)
+	require.Contains(t, err.Error(), "key:one")
+
+	parent, err := NewInjector(newMapModule("one"))
+	require.NoError(t, err)
+	_, err = parent.NewChildInjector(newMapModule("one"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeDuplicateMapKey)
+}
@@ -0,0 +1,117 @@
+package inject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type PopulateOptional struct {
+	Simple   SimpleInterface `inject:",optional"`
+	Bar      BarInterface    `inject:",optional"`
+	TagOne   SimpleInterface `inject:"tagOne,optional"`
+	TagTwo   SimpleInterface `inject:"tagTwo,optional"`
+	Optional Optional[SimpleInterface]
+}
+
+func TestOptionalStructFields(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.BindTagged("tagOne", (*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"one"})
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			populateOptional := &PopulateOptional{}
+			require.NoError(t, injector.Populate(populateOptional))
+			require.Equal(t, "hello", populateOptional.Simple.Foo())
+			require.Nil(t, populateOptional.Bar)
+			require.Equal(t, "one", populateOptional.TagOne.Foo())
+			require.Nil(t, populateOptional.TagTwo)
+			require.True(t, populateOptional.Optional.Present)
+			require.Equal(t, "hello", populateOptional.Optional.Value.Foo())
+		})
+	}
+}
+
+func TestOptionalTaggedConstructor(t *testing.T) {
+	module := NewModule()
+	module.BindTagged("tagOne", (*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"one"})
+	module.Bind((*SecondInterface)(nil)).ToTaggedConstructor(func(str struct {
+		S SimpleInterface `inject:"tagOne"`
+		B BarInterface    `inject:"tagTwo,optional"`
+	}) (SecondInterface, error) {
+		return &SecondPtrStruct{str.S, str.B}, nil
+	})
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			object, err := injector.Get((*SecondInterface)(nil))
+			require.NoError(t, err)
+			require.Equal(t, "one", object.(SecondInterface).Foo().Foo())
+			require.Nil(t, object.(SecondInterface).Bar())
+		})
+	}
+}
+
+func TestOptionalConstructorParameter(t *testing.T) {
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	module.Bind((*SecondInterface)(nil)).ToConstructor(func(s Optional[SimpleInterface], b Optional[BarInterface]) (SecondInterface, error) {
+		require.False(t, s.Present)
+		require.Nil(t, s.Value)
+		require.True(t, b.Present)
+		return &SecondPtrStruct{s.Value, b.Value}, nil
+	})
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			object, err := injector.Get((*SecondInterface)(nil))
+			require.NoError(t, err)
+			require.Equal(t, 1, object.(SecondInterface).Bar().Bar())
+
+			values, err := injector.Call(func(i Optional[int]) int { return i.Value })
+			require.NoError(t, err)
+			require.Equal(t, 0, values[0])
+		})
+	}
+}
+
+func TestOptionalRequiredStillValidated(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).ToConstructor(func(s Optional[SimpleInterface], b BarInterface) (SecondInterface, error) {
+		return &SecondPtrStruct{s.Value, b}, nil
+	})
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeNoBinding)
+	require.Contains(t, err.Error(), "BarInterface")
+}
+
+func TestOptionalPointerNotSupported(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).ToConstructor(func(s *Optional[SimpleInterface]) (SecondInterface, error) {
+		return &SecondPtrStruct{s.Value, nil}, nil
+	})
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeNotSupportedYet)
+
+	module = NewModule()
+	module.Bind((*SecondInterface)(nil)).ToTaggedConstructor(func(str struct {
+		S *Optional[SimpleInterface]
+	}) (SecondInterface, error) {
+		return &SecondPtrStruct{str.S.Value, nil}, nil
+	})
+	_, err = NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeNotSupportedYet)
+}
+
+func TestOptionalUnknownTagOption(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).ToTaggedConstructor(func(str struct {
+		S SimpleInterface `inject:",optinal"`
+	}) (SecondInterface, error) {
+		return &SecondPtrStruct{str.S, nil}, nil
+	})
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeUnknownTagOption)
+}
@@ -0,0 +1,244 @@
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Stage is the stage an Injector is created for, which decides when singletons
+// are constructed.
+type Stage int
+
+const (
+	// Development constructs singletons when they are first gotten, except
+	// those bound with SingletonBuilder.Eagerly(). This is the default.
+	Development Stage = iota
+	// Production constructs all singletons when the Injector is created, in
+	// dependency order, so that a constructor that fails makes
+	// NewInjectorWithOptions fail.
+	Production
+	// Tool constructs singletons when they are first gotten, including those
+	// bound with SingletonBuilder.Eagerly(), whose functions are not called.
+	// This is meant for tests and tools that only inspect the bindings.
+	Tool
+)
+
+var stageStrings = map[Stage]string{
+	Development: "Development",
+	Production:  "Production",
+	Tool:        "Tool",
+}
+
+func (s Stage) String() string {
+	if str, ok := stageStrings[s]; ok {
+		return str
+	}
+	return fmt.Sprintf("Stage(%d)", int(s))
+}
+
+// Logger logs what an Injector does, such as replacing a binding with
+// LenientOverrides, and a summary of the errors creating the Injector.
+// *log.Logger implements Logger.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// Observer is notified of every value an Injector gets, including the
+// dependencies of the values that are requested.
+type Observer interface {
+	// Resolved is called after getting the value of the binding for key, with
+	// the time it took including getting its dependencies, and the error if
+	// getting the value failed.
+	Resolved(key Key, duration time.Duration, err error)
+}
+
+// Options configures an Injector created with NewInjectorWithOptions. The zero
+// Options creates the same Injector as NewInjector. Child injectors and the
+// injectors of private modules have the Options of their parent.
+type Options struct {
+	// The Stage the Injector is created for, Development by default.
+	Stage Stage
+	// If true, a binding for a binding key that is already bound replaces the
+	// binding instead of returning ErrAlreadyBound, and is logged to the Logger.
+	// The binding of the last module wins, as for Override(). Bindings of a
+	// parent injector are replaced in the child injector only.
+	LenientOverrides bool
+	// The Logger to log to, or nil to not log.
+	Logger Logger
+	// The Observer to notify of every value gotten, or nil.
+	Observer Observer
+	// The key of the struct tags of tagged constructors and functions, and of
+	// the structs passed to Injector.Populate, "inject" by default.
+	TagKey string
+	// If true, only the bindings that eager singletons depend on, directly or
+	// through other bindings, are validated when the Injector is created. The
+	// dependencies of the other bindings are only checked when they are gotten,
+	// which lets tools create an Injector for a part of a large set of modules.
+	// Dependency cycles are still detected for all bindings.
+	SkipUnreachableValidation bool
+}
+
+func (o *Options) tagKey() string {
+	if o.TagKey == "" {
+		return taggedFuncStructFieldTag
+	}
+	return o.TagKey
+}
+
+func (i *injector) logf(format string, args ...interface{}) {
+	if i.options.Logger != nil {
+		i.options.Logger.Printf(format, args...)
+	}
+}
+
+// singletonBindingKeys returns the binding keys of the singleton constructor
+// bindings of the injector and its private injectors, see
+// isSingletonConstructorBinding, which are constructed when the injector is
+// created in the Production stage. The binding keys are sorted so that every singleton comes after the singletons it depends on.
+func singletonBindingKeys(injector *injector) []injectorBindingKey {
+	var singletons []injectorBindingKey
+	visited := make(map[injectorBindingKey]bool)
+	for _, root := range unsortedSingletonBindingKeys(injector) {
+		singletons = appendSingletonBindingKeys(singletons, root, visited)
+	}
+	return singletons
+}
+
+func unsortedSingletonBindingKeys(injector *injector) []injectorBindingKey {
+	var singletons []injectorBindingKey
+	for _, bindingKey := range sortedBindingKeys(injector.bindings) {
+		if isSingletonConstructorBinding(injector.bindings[bindingKey]) {
+			singletons = append(singletons, injectorBindingKey{injector, bindingKey})
+		}
+	}
+	for _, privateInjector := range injector.privateInjectors {
+		singletons = append(singletons, unsortedSingletonBindingKeys(privateInjector)...)
+	}
+	return singletons
+}
+
+// appendSingletonBindingKeys appends the singletons that key depends on, and key
+// if it is a singleton, in a depth-first search of the dependency graph
+func appendSingletonBindingKeys(singletons []injectorBindingKey, key injectorBindingKey, visited map[injectorBindingKey]bool) []injectorBindingKey {
+	injector, binding := key.injector.findBinding(key.bindingKey)
+	if binding == nil {
+		return singletons
+	}
+	key.injector = injector
+	if visited[key] {
+		return singletons
+	}
+	// visited before the dependencies, which may have cycles through providers
+	visited[key] = true
+	if exposedBinding, ok := binding.(*exposedBinding); ok {
+		singletons = appendSingletonBindingKeys(singletons, injectorBindingKey{exposedBinding.privateInjector, key.bindingKey}, visited)
+	}
+	for _, dependency := range binding.dependencies() {
+		singletons = appendSingletonBindingKeys(singletons, injectorBindingKey{injector, dependency.bindingKey}, visited)
+	}
+	if isSingletonConstructorBinding(binding) {
+		singletons = append(singletons, key)
+	}
+	return singletons
+}
+
+// isSingletonConstructorBinding returns true for singleton constructor bindings,
+// and for set and map bindings with singleton constructor elements, which are
+// only constructed by getting the set or map
+func isSingletonConstructorBinding(binding resolvedBinding) bool {
+	switch binding := binding.(type) {
+	case *singletonConstructorBinding, *taggedSingletonConstructorBinding:
+		return true
+	case *resolvedSetBinding:
+		return anySingletonConstructorBinding(binding.elements)
+	case *resolvedMapBinding:
+		return anySingletonConstructorBinding(binding.elements)
+	}
+	return false
+}
+
+func anySingletonConstructorBinding(bindings []resolvedBinding) bool {
+	for _, binding := range bindings {
+		if isSingletonConstructorBinding(binding) {
+			return true
+		}
+	}
+	return false
+}
+
+// reachableBindingKeys returns the binding keys of the bindings that the eager
+// singletons and the singletons depend on, directly or through other bindings,
+// with the injector that has the binding
+func reachableBindingKeys(eager []eagerSingleton, singletons []injectorBindingKey) map[injectorBindingKey]bool {
+	reachable := make(map[injectorBindingKey]bool)
+	stack := append([]injectorBindingKey(nil), singletons...)
+	for _, e := range eager {
+		for _, bindingKey := range e.bindingKeys {
+			stack = append(stack, injectorBindingKey{e.injector, bindingKey})
+		}
+		if e.fn != nil {
+			for _, dependency := range e.fnDependencies() {
+				stack = append(stack, injectorBindingKey{e.injector, dependency.bindingKey})
+			}
+		}
+	}
+	for len(stack) > 0 {
+		key := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		injector, binding := key.injector.findBinding(key.bindingKey)
+		if binding == nil {
+			continue
+		}
+		key.injector = injector
+		if reachable[key] {
+			continue
+		}
+		reachable[key] = true
+		if exposedBinding, ok := binding.(*exposedBinding); ok {
+			stack = append(stack, injectorBindingKey{exposedBinding.privateInjector, key.bindingKey})
+		}
+		for _, dependency := range binding.dependencies() {
+			stack = append(stack, injectorBindingKey{injector, dependency.bindingKey})
+		}
+	}
+	return reachable
+}
+
+// logErrors logs a summary of the errors creating the injector. In the
+// Production stage, the errors are counted by type, otherwise every error is
+// logged with its binding key and tags.
+func (i *injector) logErrors(err error) {
+	if i.options.Logger == nil {
+		return
+	}
+	errs := splitErrors(err)
+	stage := i.options.Stage
+	if stage != Production {
+		lines := make([]string, len(errs))
+		for ii, err := range errs {
+			lines[ii] = fmt.Sprintf("%d) %s", ii+1, err.Error())
+		}
+		i.logf("inject: %d error(s) creating injector in %s stage:\n%s", len(errs), stage, strings.Join(lines, "\n"))
+		return
+	}
+	var errorTypes []string
+	counts := make(map[string]int)
+	for _, err := range errs {
+		errorType := "other"
+		var injectErr *Error
+		if errors.As(err, &injectErr) {
+			errorType = injectErr.errorType
+		}
+		if counts[errorType] == 0 {
+			errorTypes = append(errorTypes, errorType)
+		}
+		counts[errorType]++
+	}
+	lines := make([]string, len(errorTypes))
+	for ii, errorType := range errorTypes {
+		lines[ii] = fmt.Sprintf("%dx %s", counts[errorType], errorType)
+	}
+	i.logf("inject: %d error(s) creating injector in %s stage: %s", len(errs), stage, strings.Join(lines, ", "))
+}
@@ -0,0 +1,245 @@
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (r *recordingLogger) Printf(format string, args ...interface{}) {
+	r.lines = append(r.lines, fmt.Sprintf(format, args...))
+}
+
+type recordingObserver struct {
+	keys []Key
+	errs []error
+}
+
+func (r *recordingObserver) Resolved(key Key, duration time.Duration, err error) {
+	r.keys = append(r.keys, key)
+	r.errs = append(r.errs, err)
+}
+
+func createBarInterfaceErr() (BarInterface, error) {
+	return nil, errors.New("XYZ")
+}
+
+func TestOptionsStage(t *testing.T) {
+	count := 0
+	newModule := func() Module {
+		module := NewModule()
+		module.Bind((*SimpleInterface)(nil)).ToSingletonConstructor(func() SimpleInterface {
+			count++
+			return &SimplePtrStruct{"hello"}
+		})
+		return module
+	}
+	_, err := NewInjectorWithOptions(Options{Stage: Development}, newModule())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	injector, err := NewInjectorWithOptions(Options{Stage: Production}, newModule())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	_, err = injector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, "Production", Production.String())
+}
+
+func TestOptionsProductionStageConstructorFails(t *testing.T) {
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToSingletonConstructor(createBarInterfaceErr)
+	_, err := NewInjector(module)
+	require.NoError(t, err)
+	_, err = NewInjectorWithOptions(Options{Stage: Production}, module)
+	require.True(t, errors.Is(err, ErrConstructorFailed))
+}
+
+func TestOptionsProductionStageMultibindingConstructorFails(t *testing.T) {
+	module := NewModule()
+	module.BindSet((*BarInterface)(nil)).AddSingletonConstructor(createBarInterfaceErr)
+	_, err := NewInjector(module)
+	require.NoError(t, err)
+	_, err = NewInjectorWithOptions(Options{Stage: Production}, module)
+	require.True(t, errors.Is(err, ErrConstructorFailed))
+
+	module = NewModule()
+	module.BindMap((*BarInterface)(nil)).AddSingletonConstructor("bar", createBarInterfaceErr)
+	_, err = NewInjector(module)
+	require.NoError(t, err)
+	_, err = NewInjectorWithOptions(Options{Stage: Production}, module)
+	require.True(t, errors.Is(err, ErrConstructorFailed))
+}
+
+func TestOptionsProductionStageDependencyOrder(t *testing.T) {
+	var constructed []string
+	module := NewModule()
+	// sorted before SimpleInterface, which it does not construct because it gets a Provider
+	module.Bind((*BarInterface)(nil)).ToSingletonConstructor(func(Provider[SimpleInterface]) BarInterface {
+		constructed = append(constructed, "bar")
+		return &BarPtrStruct{1}
+	})
+	module.Bind((*SimpleInterface)(nil)).ToSingletonConstructor(func() SimpleInterface {
+		constructed = append(constructed, "simple")
+		return &SimplePtrStruct{"hello"}
+	})
+	_, err := NewInjectorWithOptions(Options{Stage: Production}, module)
+	require.NoError(t, err)
+	require.Equal(t, []string{"simple", "bar"}, constructed)
+}
+
+func TestOptionsToolStage(t *testing.T) {
+	called := false
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingletonConstructor(createSimpleInterface).EagerlyAndCall(func(SimpleInterface) {
+		called = true
+	})
+	injector, err := NewInjectorWithOptions(Options{Stage: Tool}, module)
+	require.NoError(t, err)
+	require.False(t, called)
+	require.False(t, injector.Bindings()[1].Constructed)
+	_, err = NewInjector(module)
+	require.NoError(t, err)
+	require.True(t, called)
+}
+
+func TestOptionsErrorSummary(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).ToConstructor(createSecondInterfaceErrNoBinding)
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"one"})
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"two"})
+
+	logger := &recordingLogger{}
+	_, err := NewInjectorWithOptions(Options{Logger: logger}, module)
+	require.Error(t, err)
+	require.Len(t, logger.lines, 1)
+	require.Contains(t, logger.lines[0], "inject: 3 error(s) creating injector in Development stage:\n1) inject: No binding")
+	require.Contains(t, logger.lines[0], "\n3) inject: No binding")
+
+	logger = &recordingLogger{}
+	_, err = NewInjectorWithOptions(Options{Stage: Production, Logger: logger}, module)
+	require.Error(t, err)
+	require.Equal(t, []string{"inject: 3 error(s) creating injector in Production stage: 2x No binding for binding key, 1x Already found a binding for this binding key"}, logger.lines)
+}
+
+func TestOptionsLenientOverrides(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"one"})
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"two"})
+	otherModule := NewModule()
+	otherModule.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	lastModule := NewModule()
+	lastModule.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{2})
+
+	_, err := NewInjector(module, otherModule, lastModule)
+	require.Equal(t, 2, countErrors(err, ErrAlreadyBound))
+
+	logger := &recordingLogger{}
+	injector, err := NewInjectorWithOptions(Options{LenientOverrides: true, Logger: logger}, module, otherModule, lastModule)
+	require.NoError(t, err)
+	object, err := injector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "two", object.(SimpleInterface).Foo())
+	object, err = injector.Get((*BarInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, 2, object.(BarInterface).Bar())
+	require.Len(t, logger.lines, 2)
+
+	// child injectors replace the bindings of their parent
+	childModule := NewModule()
+	childModule.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{3})
+	childInjector, err := injector.NewChildInjector(childModule)
+	require.NoError(t, err)
+	object, err = childInjector.Get((*BarInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, 3, object.(BarInterface).Bar())
+	object, err = injector.Get((*BarInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, 2, object.(BarInterface).Bar())
+	require.Len(t, logger.lines, 3)
+}
+
+func TestOptionsObserver(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.Bind((*BarInterface)(nil)).ToSingletonConstructor(createBarInterfaceErr)
+	module.Bind((*SecondInterface)(nil)).ToConstructor(createSecondInterface)
+	observer := &recordingObserver{}
+	injector, err := NewInjectorWithOptions(Options{Observer: observer}, module)
+	require.NoError(t, err)
+	_, err = injector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	_, err = injector.Get((*SecondInterface)(nil))
+	require.Error(t, err)
+	// dependencies are observed before the binding that depends on them
+	require.Equal(t, []Key{
+		{simpleInterfaceReflectType, ""},
+		{simpleInterfaceReflectType, ""},
+		{barInterfaceReflectType, ""},
+		{secondInterfaceReflectType, ""},
+	}, observer.keys)
+	require.NoError(t, observer.errs[0])
+	require.NoError(t, observer.errs[1])
+	require.True(t, errors.Is(observer.errs[2], ErrConstructorFailed))
+	require.True(t, errors.Is(observer.errs[3], ErrConstructorFailed))
+}
+
+func TestOptionsTagKey(t *testing.T) {
+	module := NewModule()
+	module.BindTaggedString("name").ToSingleton("hello")
+	module.Bind((*SimpleInterface)(nil)).ToTaggedConstructor(func(s struct {
+		Name string `di:"name"`
+	}) SimpleInterface {
+		return &SimplePtrStruct{s.Name}
+	})
+	// the field is not tagged with the default tag key, so it depends on an untagged string
+	_, err := NewInjector(module)
+	require.True(t, errors.Is(err, ErrNoBinding))
+
+	injector, err := NewInjectorWithOptions(Options{TagKey: "di"}, module)
+	require.NoError(t, err)
+	object, err := injector.Get((*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "hello", object.(SimpleInterface).Foo())
+	values, err := injector.CallTagged(func(s struct {
+		Name string `di:"name"`
+	}) string {
+		return s.Name
+	})
+	require.NoError(t, err)
+	require.Equal(t, "hello", values[0])
+	populateStruct := &struct {
+		Name string `di:"name"`
+	}{}
+	require.NoError(t, injector.Populate(populateStruct))
+	require.Equal(t, "hello", populateStruct.Name)
+}
+
+func TestOptionsSkipUnreachableValidation(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	module.Bind((*SecondInterface)(nil)).ToSingletonConstructor(createSecondInterface).Eagerly()
+	module.BindTagged("unreachable", (*SecondInterface)(nil)).ToConstructor(createSecondInterfaceErrNoBinding)
+	_, err := NewInjector(module)
+	require.True(t, errors.Is(err, ErrNoBinding))
+
+	injector, err := NewInjectorWithOptions(Options{SkipUnreachableValidation: true}, module)
+	require.NoError(t, err)
+	_, err = injector.GetTagged("unreachable", (*SecondInterface)(nil))
+	require.True(t, errors.Is(err, ErrNoBinding))
+
+	// bindings that eager singletons depend on are validated
+	otherModule := NewModule()
+	otherModule.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	otherModule.Bind((*SecondInterface)(nil)).ToSingletonConstructor(createSecondInterface).Eagerly()
+	_, err = NewInjectorWithOptions(Options{SkipUnreachableValidation: true}, otherModule)
+	require.True(t, errors.Is(err, ErrNoBinding))
+}
@@ -0,0 +1,116 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+type privateModule struct {
+	*module
+	exposed []bindingKey
+}
+
+func newPrivateModule() *privateModule {
+	return &privateModule{newModule(), nil}
+}
+
+func (p *privateModule) Expose(froms ...interface{}) {
+	p.expose(newBindingKey, froms)
+}
+
+func (p *privateModule) ExposeTagged(tag string, froms ...interface{}) {
+	if !p.verifyTag(tag) {
+		return
+	}
+	p.expose(func(fromReflectType reflect.Type) bindingKey { return newTaggedBindingKey(fromReflectType, tag) }, froms)
+}
+
+func (p *privateModule) expose(newBindingKeyFunc func(reflect.Type) bindingKey, froms []interface{}) {
+	if len(froms) == 0 {
+		p.addBindingError(ErrNil)
+		return
+	}
+	for _, from := range froms {
+		fromReflectType := reflectTypeOf(from)
+		if fromReflectType == nil {
+			p.addBindingError(ErrNil)
+			continue
+		}
+		p.exposed = append(p.exposed, newBindingKeyFunc(fromReflectType))
+	}
+}
+
+func (p *privateModule) String() string {
+	return fmt.Sprintf("privateModule{%s}", p.module.String())
+}
+
+// exposedBinding is a binding of an injector that gets the value of the binding
+// of a private injector
+type exposedBinding struct {
+	bindingKey      bindingKey
+	privateInjector *injector
+}
+
+func (e *exposedBinding) String() string {
+	return fmt.Sprintf("exposed{%s}", e.privateBinding().String())
+}
+
+// validate returns nil, the private injector validates the private binding
+func (e *exposedBinding) validate() error {
+	return nil
+}
+
+func (e *exposedBinding) get(ctx context.Context, r *resolution) (interface{}, error) {
+	return e.privateBinding().get(ctx, r)
+}
+
+func (e *exposedBinding) constructed() bool {
+	return e.privateBinding().constructed()
+}
+
+func (e *exposedBinding) kind() Kind {
+	return ExposedKind
+}
+
+// dependencies returns nil, the dependencies of the private binding are not visible
+func (e *exposedBinding) dependencies() []dependency {
+	return nil
+}
+
+func (e *exposedBinding) privateBinding() resolvedBinding {
+	return e.privateInjector.bindings[e.bindingKey]
+}
+
+// exposeBindings installs the bindings of the private injector for the exposed binding
+// keys to the injector
+func exposeBindings(injector *injector, privateInjector *injector, exposed []bindingKey) []error {
+	var errs []error
+	for _, bindingKey := range exposed {
+		if _, ok := privateInjector.bindings[bindingKey]; !ok {
+			errs = append(errs, ErrNoBinding.withBindingKey(bindingKey).withTag("exposed", true))
+			continue
+		}
+		if foundBinding, err := injector.getBinding(bindingKey); err == nil {
+			errs = append(errs, ErrAlreadyBound.withBindingKey(bindingKey).withTag("foundBinding", foundBinding).withTag("exposed", true))
+			continue
+		}
+		injector.bindings[bindingKey] = &exposedBinding{bindingKey, privateInjector}
+		injector.declaringModules[bindingKey] = privateInjector.declaringModules[bindingKey]
+	}
+	return errs
+}
+
+// hasPrivateBinding returns true if a private injector of the injector has a
+// binding for the binding key
+func (i *injector) hasPrivateBinding(bindingKey bindingKey) bool {
+	for _, privateInjector := range i.privateInjectors {
+		if _, ok := privateInjector.bindings[bindingKey]; ok {
+			return true
+		}
+		if privateInjector.hasPrivateBinding(bindingKey) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newPrivateSecondInterfaceModule(foo string) PrivateModule {
+	privateModule := NewPrivateModule()
+	privateModule.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{foo})
+	privateModule.BindTagged(foo, (*SecondInterface)(nil)).ToConstructor(createSecondInterface)
+	privateModule.ExposeTagged(foo, (*SecondInterface)(nil))
+	return privateModule
+}
+
+func TestPrivateModule(t *testing.T) {
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	// the same private binding in two private modules does not collide
+	module.Install(newPrivateSecondInterfaceModule("one"), newPrivateSecondInterfaceModule("two"))
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			object, err := injector.GetTagged("one", (*SecondInterface)(nil))
+			require.NoError(t, err)
+			require.Equal(t, "one", object.(SecondInterface).Foo().Foo())
+			// the private binding depends on a binding of the injector
+			require.Equal(t, 1, object.(SecondInterface).Bar().Bar())
+			object, err = injector.GetTagged("two", (*SecondInterface)(nil))
+			require.NoError(t, err)
+			require.Equal(t, "two", object.(SecondInterface).Foo().Foo())
+
+			_, err = injector.Get((*SimpleInterface)(nil))
+			require.True(t, errors.Is(err, ErrPrivateBinding))
+		})
+	}
+}
+
+func TestPrivateModuleNewInjector(t *testing.T) {
+	privateModule := NewPrivateModule()
+	privateModule.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	privateModule.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	privateModule.Expose((*BarInterface)(nil))
+	injector, err := NewInjector(privateModule)
+	require.NoError(t, err)
+	object, err := injector.Get((*BarInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, 1, object.(BarInterface).Bar())
+	bindings := injector.Bindings()
+	require.Len(t, bindings, 2)
+	require.Equal(t, Key{barInterfaceReflectType, ""}, bindings[0].Key)
+	require.Equal(t, ExposedKind, bindings[0].Kind)
+	require.Equal(t, privateModule.Bindings()[0].Module, bindings[0].Module)
+}
+
+func TestPrivateModuleDependOnPrivateBinding(t *testing.T) {
+	privateModule := NewPrivateModule()
+	privateModule.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	module.Bind((*SecondInterface)(nil)).ToConstructor(createSecondInterface)
+	module.Install(privateModule)
+	_, err := NewInjector(module)
+	require.True(t, errors.Is(err, ErrPrivateBinding))
+	require.Contains(t, err.Error(), "SimpleInterface")
+}
+
+func TestPrivateModuleDependencyCycle(t *testing.T) {
+	privateModule := NewPrivateModule()
+	privateModule.Bind((*SimpleInterface)(nil)).ToSingletonConstructor(func(b BarInterface) SimpleInterface {
+		return &SimplePtrStruct{"hello"}
+	})
+	privateModule.Expose((*SimpleInterface)(nil))
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToSingletonConstructor(func(s SimpleInterface) BarInterface {
+		return &BarPtrStruct{1}
+	})
+	module.Install(privateModule)
+	_, err := NewInjector(module)
+	require.True(t, errors.Is(err, ErrDependencyCycle))
+	require.Contains(t, err.Error(), "{type:*inject.BarInterface} -> {type:*inject.SimpleInterface} -> {type:*inject.BarInterface}")
+}
+
+func TestPrivateModuleExposeErrors(t *testing.T) {
+	privateModule := NewPrivateModule()
+	privateModule.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	privateModule.Expose((*SimpleInterface)(nil), (*BarInterface)(nil))
+	otherPrivateModule := NewPrivateModule()
+	otherPrivateModule.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	otherPrivateModule.Expose((*SimpleInterface)(nil))
+	module := NewModule()
+	module.Install(privateModule, otherPrivateModule)
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	// both private modules expose a binding, one exposed binding is not bound
+	require.Equal(t, 1, countErrors(err, ErrAlreadyBound))
+	require.Equal(t, 1, countErrors(err, ErrNoBinding))
+}
+
+func TestPrivateModuleEagerAndClose(t *testing.T) {
+	recorder := &stopRecorder{}
+	privateModule := NewPrivateModule()
+	privateModule.Bind(&stopRecorder{}).ToSingleton(recorder)
+	privateModule.Bind(&StoppableOne{}).ToSingletonConstructor(func(r *stopRecorder) *StoppableOne { return &StoppableOne{r} }).Eagerly()
+	injector, err := NewInjector(privateModule)
+	require.NoError(t, err)
+	require.NoError(t, injector.Close(context.Background()))
+	require.Equal(t, []string{"one"}, recorder.stopped)
+}
@@ -0,0 +1,158 @@
+package inject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type PopulateProvider struct {
+	Simple func() (SimpleInterface, error)
+	TagOne Provider[SimpleInterface] `inject:"tagOne"`
+}
+
+func TestProviderConstructorParameter(t *testing.T) {
+	count := 0
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToConstructor(func() (BarInterface, error) {
+		count++
+		return &BarPtrStruct{count}, nil
+	})
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.Bind((*SecondInterface)(nil)).ToConstructor(func(s Provider[SimpleInterface], b func() (BarInterface, error)) (SecondInterface, error) {
+		require.Equal(t, 0, count)
+		simpleInterface, err := s()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := b(); err != nil {
+			return nil, err
+		}
+		barInterface, err := b()
+		if err != nil {
+			return nil, err
+		}
+		return &SecondPtrStruct{simpleInterface, barInterface}, nil
+	})
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	object, err := injector.Get((*SecondInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "hello", object.(SecondInterface).Foo().Foo())
+	// a new value is constructed every time the provider is called
+	require.Equal(t, 2, object.(SecondInterface).Bar().Bar())
+}
+
+func TestProviderCallAndPopulate(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"hello"})
+	module.BindTagged("tagOne", (*SimpleInterface)(nil)).ToSingleton(&SimplePtrStruct{"one"})
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			values, err := injector.Call(func(s Provider[SimpleInterface]) (string, error) {
+				simpleInterface, err := s()
+				if err != nil {
+					return "", err
+				}
+				return simpleInterface.Foo(), nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, "hello", values[0])
+
+			populateProvider := &PopulateProvider{}
+			require.NoError(t, injector.Populate(populateProvider))
+			simpleInterface, err := populateProvider.Simple()
+			require.NoError(t, err)
+			require.Equal(t, "hello", simpleInterface.Foo())
+			simpleInterface, err = populateProvider.TagOne()
+			require.NoError(t, err)
+			require.Equal(t, "one", simpleInterface.Foo())
+		})
+	}
+}
+
+func TestProviderOptionalNotSupported(t *testing.T) {
+	injector, err := NewInjector()
+	require.NoError(t, err)
+	err = injector.Populate(&struct {
+		Simple Provider[SimpleInterface] `inject:",optional"`
+	}{})
+	require.True(t, errors.Is(err, ErrNotSupportedYet))
+
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).ToTaggedConstructor(func(s struct {
+		Simple func() (SimpleInterface, error) `inject:",optional"`
+	}) (SecondInterface, error) {
+		return nil, nil
+	})
+	_, err = NewInjector(module)
+	require.True(t, errors.Is(err, ErrNotSupportedYet))
+}
+
+func TestProviderReturnsConstructorError(t *testing.T) {
+	module := NewModule()
+	module.Bind((*BarInterface)(nil)).ToConstructor(createEvilBarInterfaceErr)
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	values, err := injector.Call(func(b Provider[BarInterface]) error {
+		_, err := b()
+		return err
+	})
+	require.NoError(t, err)
+	require.Error(t, values[0].(error))
+}
+
+func TestProviderUnboundFailsValidation(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SecondInterface)(nil)).ToConstructor(func(s Provider[SimpleInterface]) (SecondInterface, error) {
+		return nil, nil
+	})
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeNoBinding)
+}
+
+func TestProviderBreaksCycle(t *testing.T) {
+	var provider Provider[*CycleA]
+	module := NewModule()
+	module.Bind(&CycleA{}).ToSingletonConstructor(createCycleA)
+	module.Bind(&CycleB{}).ToSingletonConstructor(func(a Provider[*CycleA]) (*CycleB, error) {
+		provider = a
+		return &CycleB{}, nil
+	})
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	cycleA, err := injector.Get(&CycleA{})
+	require.NoError(t, err)
+	providedCycleA, err := provider()
+	require.NoError(t, err)
+	require.True(t, cycleA == providedCycleA)
+}
+
+func TestProviderCalledInCycle(t *testing.T) {
+	for _, singleton := range []bool{true, false} {
+		module := NewModule()
+		createA := func(b Provider[*CycleB]) (*CycleA, error) {
+			if _, err := b(); err != nil {
+				return nil, err
+			}
+			return &CycleA{}, nil
+		}
+		if singleton {
+			module.Bind(&CycleA{}).ToSingletonConstructor(createA)
+			module.Bind(&CycleB{}).ToSingletonConstructor(createCycleB)
+		} else {
+			module.Bind(&CycleA{}).ToConstructor(createA)
+			module.Bind(&CycleB{}).ToConstructor(createCycleB)
+		}
+		injector, err := NewInjector(module)
+		require.NoError(t, err)
+		_, err = injector.Get(&CycleA{})
+		require.True(t, errors.Is(err, ErrDependencyCycle))
+		var resolutionErr *ResolutionError
+		require.True(t, errors.As(err, &resolutionErr))
+		require.Equal(t, []Key{{reflect.TypeOf(&CycleA{}), ""}, {reflect.TypeOf(&CycleB{}), ""}, {reflect.TypeOf(&CycleA{}), ""}}, resolutionErr.Path)
+	}
+}
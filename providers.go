@@ -0,0 +1,120 @@
+package inject
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const providerMethodPrefix = "Provide"
+
+// ProviderOptions configures how ModuleFromProviders binds a provider method.
+type ProviderOptions struct {
+	// The tag of the binding, or empty for an untagged binding.
+	Tag string
+	// If true, the method is bound as a singleton constructor.
+	Singleton bool
+	// If true, the method is bound as an eager singleton constructor, see
+	// SingletonBuilder.Eagerly.
+	Eager bool
+}
+
+// ProviderBindingOptions can be implemented by the providers passed to
+// ModuleFromProviders to bind provider methods with ProviderOptions, by method name.
+type ProviderBindingOptions interface {
+	BindingOptions() map[string]ProviderOptions
+}
+
+// ModuleFromProviders creates a Module that binds every exported method of
+// providers whose name starts with "Provide", like Guice's @Provides methods.
+// The first return type of a method is bound to the method as a constructor, as
+// with Module.BindConstructor, or as a tagged constructor if the method takes an
+// anonymous struct. The fields of providers are available to the methods, for
+// example as configuration:
+//
+//	type Providers struct {
+//		DSN string
+//	}
+//
+//	func (p *Providers) ProvideDB() (*sql.DB, error) {
+//		return sql.Open("postgres", p.DSN)
+//	}
+//
+//	func (p *Providers) BindingOptions() map[string]inject.ProviderOptions {
+//		return map[string]inject.ProviderOptions{
+//			"ProvideDB": {Singleton: true},
+//		}
+//	}
+//
+//	module := inject.ModuleFromProviders(&Providers{DSN: dsn})
+//
+// Methods are bound as unscoped constructors unless providers implements
+// ProviderBindingOptions.
+func ModuleFromProviders(providers interface{}) Module {
+	m := newModule()
+	if providers == nil {
+		m.addBindingError(ErrNil)
+		return m
+	}
+	var options map[string]ProviderOptions
+	if bindingOptions, ok := providers.(ProviderBindingOptions); ok {
+		options = bindingOptions.BindingOptions()
+	}
+	providersReflectValue := reflect.ValueOf(providers)
+	providersReflectType := providersReflectValue.Type()
+	bound := make(map[string]bool)
+	for i := 0; i < providersReflectType.NumMethod(); i++ {
+		name := providersReflectType.Method(i).Name
+		if !strings.HasPrefix(name, providerMethodPrefix) {
+			continue
+		}
+		m.bindProvider(name, providersReflectValue.Method(i).Interface(), options[name])
+		bound[name] = true
+	}
+	var unknownNames []string
+	for name := range options {
+		if !bound[name] {
+			unknownNames = append(unknownNames, name)
+		}
+	}
+	sort.Strings(unknownNames)
+	for _, name := range unknownNames {
+		m.addBindingError(ErrNoProviderMethod.withTag("providers", providersReflectType).withTag("method", name))
+	}
+	return m
+}
+
+// bindProvider binds the method named name as a constructor of its first return type
+func (m *module) bindProvider(name string, method interface{}, options ProviderOptions) {
+	methodReflectType := reflect.TypeOf(method)
+	if methodReflectType.NumOut() == 0 {
+		m.addBindingError(ErrConstructorReturnValuesInvalid.withTag("method", name))
+		return
+	}
+	out := bindingKeyReflectTypeOf(methodReflectType.Out(0))
+	var builder Builder
+	if options.Tag != "" {
+		builder = m.BindTagged(options.Tag, out)
+	} else {
+		builder = m.Bind(out)
+	}
+	tagged := verifyTaggedFuncParameters(methodReflectType) == nil
+	if !options.Singleton && !options.Eager {
+		if tagged {
+			builder.ToTaggedConstructor(method)
+		} else {
+			builder.ToConstructor(method)
+		}
+		return
+	}
+	var singletonBuilder SingletonBuilder
+	if tagged {
+		singletonBuilder = builder.ToTaggedSingletonConstructor(method)
+	} else {
+		singletonBuilder = builder.ToSingletonConstructor(method)
+	}
+	// nil if the return type cannot be bound
+	if options.Eager && singletonBuilder != nil {
+		singletonBuilder.Eagerly()
+	}
+}
@@ -0,0 +1,87 @@
+package inject
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testProviders struct {
+	foo      string
+	bar      int
+	barCount int
+}
+
+func (p *testProviders) ProvideSimple() SimpleInterface {
+	return &SimplePtrStruct{p.foo}
+}
+
+func (p *testProviders) ProvideBar() (BarInterface, error) {
+	p.barCount++
+	return &BarPtrStruct{p.bar}, nil
+}
+
+func (p *testProviders) ProvideSecond(s SimpleInterface, b BarInterface) SecondInterface {
+	return &SecondPtrStruct{s, b}
+}
+
+func (p *testProviders) ProvideTaggedSimple(str struct {
+	Simple SimpleInterface
+	Name   string `inject:"name"`
+}) SimpleInterface {
+	return &SimplePtrStruct{str.Simple.Foo() + " " + str.Name}
+}
+
+func (p *testProviders) ProvideName() string {
+	return "world"
+}
+
+// not a provider method
+func (p *testProviders) NewSimple() SimpleInterface {
+	return &SimplePtrStruct{"other"}
+}
+
+func (p *testProviders) BindingOptions() map[string]ProviderOptions {
+	return map[string]ProviderOptions{
+		"ProvideBar":          {Eager: true},
+		"ProvideTaggedSimple": {Tag: "tagged", Singleton: true},
+		"ProvideName":         {Tag: "name"},
+	}
+}
+
+func TestModuleFromProviders(t *testing.T) {
+	providers := &testProviders{foo: "hello", bar: 1}
+	injector, err := NewInjector(ModuleFromProviders(providers))
+	require.NoError(t, err)
+	// the eager singleton is already constructed
+	require.Equal(t, 1, providers.barCount)
+	object, err := injector.Get((*SecondInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "hello", object.(SecondInterface).Foo().Foo())
+	require.Equal(t, 1, object.(SecondInterface).Bar().Bar())
+	require.Equal(t, 1, providers.barCount)
+	object, err = injector.GetTagged("tagged", (*SimpleInterface)(nil))
+	require.NoError(t, err)
+	require.Equal(t, "hello world", object.(SimpleInterface).Foo())
+	bindings := injector.Bindings()
+	require.Len(t, bindings, 6)
+}
+
+type invalidProviders struct{}
+
+func (invalidProviders) ProvideNothing() {}
+
+func (invalidProviders) BindingOptions() map[string]ProviderOptions {
+	return map[string]ProviderOptions{"ProvideMissing": {}}
+}
+
+func TestModuleFromProvidersErrors(t *testing.T) {
+	_, err := NewInjector(ModuleFromProviders(invalidProviders{}))
+	require.True(t, errors.Is(err, ErrConstructorReturnValuesInvalid))
+	require.True(t, errors.Is(err, ErrNoProviderMethod))
+	require.Contains(t, err.Error(), "ProvideMissing")
+
+	_, err = NewInjector(ModuleFromProviders(nil))
+	require.True(t, errors.Is(err, ErrNil))
+}
@@ -0,0 +1,151 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ResolutionError is returned when getting a value fails, with the path of
+// bindings that were being resolved when the error occurred.
+type ResolutionError struct {
+	// The keys of the bindings being resolved, from the binding that was
+	// requested to the binding that failed.
+	Path []Key
+	// The error of the binding that failed.
+	Cause error
+}
+
+func (r *ResolutionError) Error() string {
+	keyStrings := make([]string, len(r.Path))
+	for i, key := range r.Path {
+		keyStrings[i] = key.String()
+	}
+	return fmt.Sprintf("resolving %s: %s", strings.Join(keyStrings, " -> "), r.Cause.Error())
+}
+
+// Unwrap returns the error of the binding that failed.
+func (r *ResolutionError) Unwrap() error {
+	return r.Cause
+}
+
+// resolution is a binding being resolved, linked to the resolution of the
+// binding that depends on it. It is passed along with the context of the caller
+// instead of in it, so that constructors get the context of the caller as is.
+// The nil *resolution is the root, before any binding is resolved.
+type resolution struct {
+	parent     *resolution
+	bindingKey bindingKey
+	// nil if there is no binding for bindingKey
+	binding resolvedBinding
+	// set to 1 once the value of the binding was gotten
+	done int32
+}
+
+// with returns the resolution of bindingKey as a dependency of r
+func (r *resolution) with(bindingKey bindingKey, binding resolvedBinding) *resolution {
+	return &resolution{parent: r, bindingKey: bindingKey, binding: binding}
+}
+
+// resolving returns true if binding is being resolved by r or its parents, in
+// which case resolving it again would never end
+func (r *resolution) resolving(binding resolvedBinding) bool {
+	for resolution := r; resolution != nil; resolution = resolution.parent {
+		if resolution.binding == binding {
+			return true
+		}
+	}
+	return false
+}
+
+// active returns r while its binding is being resolved, and the root once its
+// value was gotten, for Providers that are called after the value they were
+// injected into was constructed
+func (r *resolution) active() *resolution {
+	if r == nil || atomic.LoadInt32(&r.done) == 1 {
+		return nil
+	}
+	return r
+}
+
+// path returns the binding keys being resolved, from the root to r
+func (r *resolution) path() []bindingKey {
+	var path []bindingKey
+	for resolution := r; resolution != nil; resolution = resolution.parent {
+		path = append(path, resolution.bindingKey)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// resolve gets the value of the binding for bindingKey, adding the path of
+// bindings being resolved to errors, and notifies the Observer of the injector
+func (i *injector) resolve(ctx context.Context, r *resolution, bindingKey bindingKey, binding resolvedBinding) (_ interface{}, retErr error) {
+	if observer := i.options.Observer; observer != nil {
+		start := time.Now()
+		defer func() {
+			observer.Resolved(newKey(bindingKey), time.Since(start), retErr)
+		}()
+	}
+	if r.resolving(binding) {
+		r = r.with(bindingKey, binding)
+		return nil, newResolutionError(r, ErrDependencyCycle.withBindingKey(bindingKey).withTag("cycle", bindingKeyPathString(r.path())))
+	}
+	r = r.with(bindingKey, binding)
+	defer atomic.StoreInt32(&r.done, 1)
+	value, err := binding.get(ctx, r)
+	if err != nil {
+		return nil, newResolutionError(r, err)
+	}
+	return value, nil
+}
+
+// newResolutionError wraps err with the path of r, unless err already has the
+// path of a binding that was resolved deeper
+func newResolutionError(r *resolution, err error) error {
+	var resolutionErr *ResolutionError
+	if errors.As(err, &resolutionErr) {
+		return err
+	}
+	path := r.path()
+	keys := make([]Key, len(path))
+	for i, bindingKey := range path {
+		keys[i] = newKey(bindingKey)
+	}
+	return &ResolutionError{keys, err}
+}
+
+type resolutionContextKey struct{}
+
+// resolutionContext passes the resolution through the context of a Scope, which
+// only gets the context, to the unscoped binding
+type resolutionContext struct {
+	context.Context
+	resolution *resolution
+}
+
+func withResolution(ctx context.Context, r *resolution) context.Context {
+	return resolutionContext{ctx, r}
+}
+
+func (r resolutionContext) Value(key interface{}) interface{} {
+	if key == (resolutionContextKey{}) {
+		return r.resolution
+	}
+	return r.Context.Value(key)
+}
+
+// splitResolution returns the context of the caller and the resolution of a
+// context returned by withResolution, which the Scope may have derived from
+func splitResolution(ctx context.Context) (context.Context, *resolution) {
+	if resolutionCtx, ok := ctx.(resolutionContext); ok {
+		return resolutionCtx.Context, resolutionCtx.resolution
+	}
+	r, _ := ctx.Value(resolutionContextKey{}).(*resolution)
+	return ctx, r
+}
@@ -0,0 +1,107 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolutionPath(t *testing.T) {
+	module := NewModule()
+	module.BindTagged("tagOne", (*SimpleInterface)(nil)).ToConstructor(func() (SimpleInterface, error) {
+		return nil, errXYZ
+	})
+	module.Bind((*BarInterface)(nil)).ToSingleton(&BarPtrStruct{1})
+	module.Bind((*SecondInterface)(nil)).ToTaggedConstructor(func(str struct {
+		S SimpleInterface `inject:"tagOne"`
+		B BarInterface
+	}) (SecondInterface, error) {
+		return &SecondPtrStruct{str.S, str.B}, nil
+	})
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			_, err := injector.Get((*SecondInterface)(nil))
+			require.Error(t, err)
+			var resolutionErr *ResolutionError
+			require.True(t, errors.As(err, &resolutionErr))
+			require.Equal(t, []Key{{secondInterfaceReflectType, ""}, {simpleInterfaceReflectType, "tagOne"}}, resolutionErr.Path)
+			require.True(t, errors.Is(err, ErrConstructorFailed))
+			require.True(t, errors.Is(err, errXYZ))
+			require.Regexp(t, "^resolving \\{type:inject.SecondInterface\\} -> \\{type:inject.SimpleInterface tag:tagOne\\}: inject: Constructor returned an error", err.Error())
+		})
+	}
+}
+
+func TestResolutionPathNoBinding(t *testing.T) {
+	injector, err := NewInjector()
+	require.NoError(t, err)
+	_, err = injector.Get((*SimpleInterface)(nil))
+	require.True(t, errors.Is(err, ErrNoBinding))
+	require.Regexp(t, "^resolving \\{type:inject.SimpleInterface\\}: inject: No binding", err.Error())
+}
+
+func TestResolutionPathNotInContext(t *testing.T) {
+	var contexts []context.Context
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).In(&countingScope{}).ToConstructor(func(ctx context.Context) SimpleInterface {
+		contexts = append(contexts, ctx)
+		return &SimplePtrStruct{"hello"}
+	})
+	module.Bind((*BarInterface)(nil)).ToConstructor(func(ctx context.Context, s SimpleInterface) BarInterface {
+		contexts = append(contexts, ctx)
+		return &BarPtrStruct{1}
+	})
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	_, err = injector.GetContext(ctx, (*BarInterface)(nil))
+	require.NoError(t, err)
+	// the constructors get the context of the caller as is, also through a Scope
+	require.Equal(t, []context.Context{ctx, ctx}, contexts)
+}
+
+func TestResolutionPathProvider(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToConstructor(func() (SimpleInterface, error) {
+		return nil, errXYZ
+	})
+	module.Bind((*BarInterface)(nil)).ToConstructor(func(provider Provider[SimpleInterface]) (BarInterface, error) {
+		if _, err := provider(); err != nil {
+			return nil, err
+		}
+		return &BarPtrStruct{1}, nil
+	})
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	_, err = injector.Get((*BarInterface)(nil))
+	var resolutionErr *ResolutionError
+	require.True(t, errors.As(err, &resolutionErr))
+	// the path of the provider is kept
+	require.Equal(t, []Key{{barInterfaceReflectType, ""}, {simpleInterfaceReflectType, ""}}, resolutionErr.Path)
+	require.True(t, errors.Is(err, errXYZ))
+}
+
+func TestConstructorPanic(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).ToSingletonConstructor(func() (SimpleInterface, error) {
+		panic("oops")
+	})
+	module.Bind((*BarInterface)(nil)).ToConstructor(func() (BarInterface, error) {
+		panic(errXYZ)
+	})
+	module.Bind((*SecondInterface)(nil)).ToConstructor(createSecondInterface)
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			_, err := injector.Get((*SecondInterface)(nil))
+			require.True(t, errors.Is(err, ErrConstructorPanicked))
+			require.Regexp(t, "^resolving \\{type:inject.SecondInterface\\} -> \\{type:inject.SimpleInterface\\}: inject: Constructor panicked", err.Error())
+			require.Contains(t, err.Error(), "panic:oops")
+
+			_, err = injector.Get((*BarInterface)(nil))
+			require.True(t, errors.Is(err, ErrConstructorPanicked))
+			require.True(t, errors.Is(err, errXYZ))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package inject
+
+import (
+	"context"
+	"sync"
+)
+
+// RequestScope is a Scope that shares one value per binding between all
+// resolutions with the same request context, see NewRequestContext.
+//
+// Resolving a binding in RequestScope with a context that was not derived
+// from NewRequestContext results in an error.
+var RequestScope Scope = requestScope{}
+
+// NewRequestContext returns a new context for a request, such as an HTTP request.
+// Values of bindings in RequestScope are constructed at most once per request.
+func NewRequestContext(parent context.Context) context.Context {
+	return context.WithValue(parent, requestScopeContextKey{}, &requestScopeValues{loaders: make(map[*requestScopedBinding]*loader)})
+}
+
+type requestScopeContextKey struct{}
+
+type requestScopeValues struct {
+	lock    sync.Mutex
+	loaders map[*requestScopedBinding]*loader
+}
+
+type requestScope struct{}
+
+func (requestScope) Scope(unscoped func(context.Context) (interface{}, error)) func(context.Context) (interface{}, error) {
+	return (&requestScopedBinding{unscoped}).get
+}
+
+func (requestScope) String() string {
+	return "RequestScope"
+}
+
+type requestScopedBinding struct {
+	unscoped func(context.Context) (interface{}, error)
+}
+
+func (r *requestScopedBinding) get(ctx context.Context) (interface{}, error) {
+	values, ok := ctx.Value(requestScopeContextKey{}).(*requestScopeValues)
+	if !ok {
+		return nil, ErrOutOfScope.withTag("scope", RequestScope)
+	}
+	values.lock.Lock()
+	l, ok := values.loaders[r]
+	if !ok {
+		l = newLoader(nil)
+		values.loaders[r] = l
+	}
+	values.lock.Unlock()
+	return l.load(func() (interface{}, error) { return r.unscoped(ctx) })
+}
@@ -0,0 +1,89 @@
+package inject
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type RequestStruct struct {
+	id int32
+}
+
+var requestStructCounter int32
+
+func createRequestStruct() (*RequestStruct, error) {
+	return &RequestStruct{atomic.AddInt32(&requestStructCounter, 1)}, nil
+}
+
+type DependsOnRequestStruct struct {
+	RequestStruct *RequestStruct
+}
+
+func TestRequestScope(t *testing.T) {
+	module := NewModule()
+	module.Bind(&RequestStruct{}).In(RequestScope).ToConstructor(createRequestStruct)
+	module.Bind(&DependsOnRequestStruct{}).ToTaggedConstructor(func(s struct{ RequestStruct *RequestStruct }) *DependsOnRequestStruct {
+		return &DependsOnRequestStruct{s.RequestStruct}
+	})
+	for _, injector := range createInjectors(t, module) {
+		t.Run(injector.name, func(t *testing.T) {
+			ctx1 := NewRequestContext(context.Background())
+			ctx2 := NewRequestContext(context.Background())
+
+			object1, err := injector.GetContext(ctx1, &RequestStruct{})
+			require.NoError(t, err)
+			object2, err := injector.GetContext(ctx1, &DependsOnRequestStruct{})
+			require.NoError(t, err)
+			require.True(t, object1.(*RequestStruct) == object2.(*DependsOnRequestStruct).RequestStruct)
+
+			object3, err := injector.GetContext(ctx2, &RequestStruct{})
+			require.NoError(t, err)
+			require.NotEqual(t, object1.(*RequestStruct).id, object3.(*RequestStruct).id)
+
+			// a context derived from a request context is in the same request
+			object4, err := injector.GetContext(context.WithValue(ctx1, contextKey{}, "value"), &RequestStruct{})
+			require.NoError(t, err)
+			require.True(t, object1 == object4)
+
+			_, err = injector.Get(&RequestStruct{})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), injectErrorTypeOutOfScope)
+		})
+	}
+}
+
+type countingScope struct {
+	calls int32
+}
+
+func (c *countingScope) Scope(unscoped func(context.Context) (interface{}, error)) func(context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&c.calls, 1)
+		return unscoped(ctx)
+	}
+}
+
+func TestCustomScope(t *testing.T) {
+	scope := &countingScope{}
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).In(scope).ToConstructor(createSimplePtrInterface)
+	injector, err := NewInjector(module)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		object, err := injector.Get((*SimpleInterface)(nil))
+		require.NoError(t, err)
+		require.Equal(t, "default", object.(SimpleInterface).Foo())
+	}
+	require.Equal(t, int32(3), scope.calls)
+}
+
+func TestScopeNotSupported(t *testing.T) {
+	module := NewModule()
+	module.Bind((*SimpleInterface)(nil)).In(RequestScope).ToSingleton(&SimplePtrStruct{"hello"})
+	_, err := NewInjector(module)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), injectErrorTypeScopeNotSupported)
+}